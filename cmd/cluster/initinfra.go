@@ -28,7 +28,10 @@ const (
 	getInitInfraLong = `Deploy initial infrastructure to kubernetes cluster such as ` +
 		`metal3.io, argo, tiller and other manifest documents with appropriate labels`
 	getInitInfraExample = `#deploy infra to cluster
-	airshipctl cluster initinfra`
+	airshipctl cluster initinfra
+
+	#show changes to be made to the cluster
+	airshipctl cluster initinfra --diff`
 )
 
 // NewCmdInitInfra creates a command to deploy initial airship infrastructure
@@ -40,7 +43,7 @@ func NewCmdInitInfra(rootSettings *environment.AirshipCTLSettings) *cobra.Comman
 		Long:    getInitInfraLong,
 		Example: getInitInfraExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return i.Run()
+			return i.Run(cmd.OutOrStdout())
 		},
 	}
 	addInitinfraFlags(i, initinfraCmd)
@@ -55,6 +58,12 @@ func addInitinfraFlags(i *initinfra.Infra, cmd *cobra.Command) {
 		false,
 		"Don't deliver documents to the cluster, simulate the changes instead")
 
+	flags.BoolVar(
+		&i.ShowDiff,
+		"diff",
+		false,
+		"Don't deliver documents to the cluster, show changes that the server would make instead")
+
 	flags.BoolVar(
 		&i.Prune,
 		"prune",
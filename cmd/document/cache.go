@@ -0,0 +1,42 @@
+package document
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// NewCacheCommand creates a new command for managing the bundle cache
+func NewCacheCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cache of built document bundles",
+	}
+
+	cacheCmd.AddCommand(NewCacheCleanCommand(rootSettings))
+	return cacheCmd
+}
+
+// NewCacheCleanCommand creates a new command removing cached bundles
+func NewCacheCleanCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	cleanCmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove all cached document bundles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache, err := document.NewBundleCache()
+			if err != nil {
+				return err
+			}
+			removed, err := cache.Clean()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %d cached bundle(s) from %s\n", removed, cache.Dir)
+			return nil
+		},
+	}
+	return cleanCmd
+}
@@ -0,0 +1,76 @@
+package document
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/diff"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// NewDiffCommand creates a new command for comparing document bundles
+func NewDiffCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	diffSettings := &diff.Settings{AirshipCTLSettings: rootSettings}
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show differences between two document bundles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return diffSettings.Diff(cmd.OutOrStdout())
+		},
+	}
+
+	addDiffFlags(diffSettings, diffCmd)
+	return diffCmd
+}
+
+// addDiffFlags adds flags for document diff sub-command
+func addDiffFlags(settings *diff.Settings, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.StringVarP(
+		&settings.ClusterType,
+		"cluster-type",
+		"t",
+		config.Ephemeral,
+		"Select cluster type to compare documents for")
+
+	flags.StringVarP(
+		&settings.Phase,
+		"phase",
+		"p",
+		config.Initinfra,
+		"Select phase to compare documents for")
+
+	flags.StringVar(
+		&settings.FromRef,
+		"from-ref",
+		"",
+		"Git revision of the primary repository to compare from (default \""+diff.DefaultFromRef+"\")")
+
+	flags.StringVar(
+		&settings.FromPath,
+		"from-path",
+		"",
+		"Kustomization directory to compare from")
+
+	flags.StringVar(
+		&settings.ToRef,
+		"to-ref",
+		"",
+		"Git revision of the primary repository to compare to, the working tree is used by default")
+
+	flags.StringVar(
+		&settings.ToPath,
+		"to-path",
+		"",
+		"Kustomization directory to compare to")
+
+	flags.StringVarP(
+		&settings.Output,
+		"output",
+		"o",
+		diff.TextFormat,
+		"Output format, one of: "+strings.Join(diff.OutputFormats(), ", "))
+}
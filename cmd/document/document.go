@@ -13,8 +13,14 @@ func NewDocumentCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Com
 		Short: "manages deployment documents",
 	}
 
+	documentRootCmd.AddCommand(NewCacheCommand(rootSettings))
+	documentRootCmd.AddCommand(NewDiffCommand(rootSettings))
+	documentRootCmd.AddCommand(NewExportCommand(rootSettings))
+	documentRootCmd.AddCommand(NewImportCommand(rootSettings))
 	documentRootCmd.AddCommand(NewDocumentPullCommand(rootSettings))
+	documentRootCmd.AddCommand(NewLockCommand(rootSettings))
 	documentRootCmd.AddCommand(NewRenderCommand(rootSettings))
+	documentRootCmd.AddCommand(NewValidateCommand(rootSettings))
 
 	return documentRootCmd
 }
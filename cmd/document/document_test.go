@@ -14,11 +14,51 @@ func TestDocument(t *testing.T) {
 			CmdLine: "",
 			Cmd:     document.NewDocumentCommand(nil),
 		},
+		{
+			Name:    "document-cache-with-defaults",
+			CmdLine: "",
+			Cmd:     document.NewCacheCommand(nil),
+		},
+		{
+			Name:    "document-cache-clean-with-help",
+			CmdLine: "-h",
+			Cmd:     document.NewCacheCleanCommand(nil),
+		},
+		{
+			Name:    "document-diff-with-help",
+			CmdLine: "-h",
+			Cmd:     document.NewDiffCommand(nil),
+		},
+		{
+			Name:    "document-export-with-help",
+			CmdLine: "-h",
+			Cmd:     document.NewExportCommand(nil),
+		},
+		{
+			Name:    "document-import-with-help",
+			CmdLine: "-h",
+			Cmd:     document.NewImportCommand(nil),
+		},
+		{
+			Name:    "document-lock-with-defaults",
+			CmdLine: "",
+			Cmd:     document.NewLockCommand(nil),
+		},
+		{
+			Name:    "document-lock-verify-with-help",
+			CmdLine: "-h",
+			Cmd:     document.NewLockVerifyCommand(nil),
+		},
 		{
 			Name:    "document-render-with-help",
 			CmdLine: "-h",
 			Cmd:     document.NewRenderCommand(nil),
 		},
+		{
+			Name:    "document-validate-with-help",
+			CmdLine: "-h",
+			Cmd:     document.NewValidateCommand(nil),
+		},
 	}
 	for _, tt := range tests {
 		testutil.RunTest(t, tt)
@@ -0,0 +1,46 @@
+package document
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/document/archive"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// NewExportCommand creates a new command exporting manifest repositories to an archive
+func NewExportCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	settings := &archive.ExportSettings{AirshipCTLSettings: rootSettings}
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export working trees of manifest repositories to an archive for offline use",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return settings.Export(cmd.OutOrStdout())
+		},
+	}
+
+	addExportFlags(settings, exportCmd)
+	return exportCmd
+}
+
+// addExportFlags adds flags for document export sub-command
+func addExportFlags(settings *archive.ExportSettings, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.StringVar(
+		&settings.Archive,
+		"archive",
+		"",
+		"Path of the gzipped tar archive to write")
+
+	err := cmd.MarkFlagRequired("archive")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	flags.StringVar(
+		&settings.SignKey,
+		"sign-key",
+		"",
+		"Path of the PEM encoded RSA private key to sign the archive with")
+}
@@ -0,0 +1,58 @@
+package document
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/document/archive"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// NewImportCommand creates a new command importing manifest repositories from an archive
+func NewImportCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	settings := &archive.ImportSettings{AirshipCTLSettings: rootSettings}
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import working trees of manifest repositories from an archive",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return settings.Import(cmd.OutOrStdout())
+		},
+	}
+
+	addImportFlags(settings, importCmd)
+	return importCmd
+}
+
+// addImportFlags adds flags for document import sub-command
+func addImportFlags(settings *archive.ImportSettings, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.StringVar(
+		&settings.Archive,
+		"archive",
+		"",
+		"Path of the archive written by document export")
+
+	err := cmd.MarkFlagRequired("archive")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	flags.StringVar(
+		&settings.VerifyKey,
+		"verify-key",
+		"",
+		"Path of the PEM encoded RSA public key, certificate or private key to verify the archive signature with")
+
+	flags.StringVar(
+		&settings.Signature,
+		"signature",
+		"",
+		"Path of the archive signature, defaults to the archive path with "+archive.SignatureExt+" appended")
+
+	flags.BoolVar(
+		&settings.Force,
+		"force",
+		false,
+		"Replace working trees existing in the target path")
+}
@@ -0,0 +1,32 @@
+package document
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/document/lock"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// NewLockCommand creates a new command for managing the manifest lock file
+func NewLockCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	lockCmd := &cobra.Command{
+		Use:   "lock",
+		Short: "Manage the lock file pinning manifest repositories to commits",
+	}
+
+	lockCmd.AddCommand(NewLockVerifyCommand(rootSettings))
+	return lockCmd
+}
+
+// NewLockVerifyCommand creates a new command checking repositories against the lock file
+func NewLockVerifyCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	settings := &lock.Settings{AirshipCTLSettings: rootSettings}
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check that working trees of manifest repositories match the lock file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return settings.Verify(cmd.OutOrStdout())
+		},
+	}
+	return verifyCmd
+}
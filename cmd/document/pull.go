@@ -14,9 +14,28 @@ func NewDocumentPullCommand(rootSettings *environment.AirshipCTLSettings) *cobra
 		Use:   "pull",
 		Short: "pulls documents from remote git repository",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return settings.Pull()
+			return settings.Pull(cmd.OutOrStdout())
 		},
 	}
 
+	addPullFlags(&settings, documentPullCmd)
 	return documentPullCmd
 }
+
+// addPullFlags adds flags for document pull sub-command
+func addPullFlags(settings *pull.Settings, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.BoolVar(
+		&settings.Update,
+		"update",
+		false,
+		"Ignore the lock file and lock repositories at commits checked out as configured")
+
+	flags.IntVarP(
+		&settings.Jobs,
+		"jobs",
+		"j",
+		pull.DefaultJobs,
+		"Number of repositories pulled concurrently")
+}
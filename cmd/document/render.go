@@ -1,11 +1,14 @@
 package document
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
 
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/document/render"
 	"opendev.org/airship/airshipctl/pkg/environment"
-	"opendev.org/airship/airshipctl/pkg/errors"
 )
 
 // NewRenderCommand create a new command for document rendering
@@ -15,7 +18,7 @@ func NewRenderCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Comma
 		Use:   "render",
 		Short: "Render documents from model",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return errors.ErrNotImplemented{}
+			return renderSettings.Render(cmd.OutOrStdout())
 		},
 	}
 
@@ -61,4 +64,38 @@ func addRenderFlags(settings *render.Settings, cmd *cobra.Command) {
 		"f",
 		"",
 		"Logical expression for document filtering")
+
+	flags.StringVarP(
+		&settings.ClusterType,
+		"cluster-type",
+		"t",
+		config.Ephemeral,
+		"Select cluster type to render documents for")
+
+	flags.StringVarP(
+		&settings.Phase,
+		"phase",
+		"p",
+		config.Initinfra,
+		"Select phase to render documents for")
+
+	flags.StringVarP(
+		&settings.OutputDir,
+		"output-dir",
+		"d",
+		"",
+		"Write each rendered document to a separate file in this directory")
+
+	flags.StringVarP(
+		&settings.Output,
+		"output",
+		"o",
+		document.YAMLFormat,
+		"Output format, one of: "+strings.Join(document.OutputFormats(), ", "))
+
+	flags.BoolVar(
+		&settings.ShowOrigin,
+		"show-origin",
+		false,
+		"Annotate documents with files, patches and transformers they come from")
 }
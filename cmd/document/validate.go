@@ -0,0 +1,53 @@
+package document
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/validate"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+const (
+	validateShort = "Validate documents against CRD schemas and check built-in objects for unknown fields"
+	validateLong  = `Validate documents of the current context.
+
+Custom resources are validated against OpenAPI v3 schemas of the CustomResourceDefinitions
+found in the same bundle. Built-in kubernetes objects are only checked for unknown fields
+and values of wrong types, their required fields, enums and formats are left to the server.`
+)
+
+// NewValidateCommand creates a new command for document validation
+func NewValidateCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	validateSettings := &validate.Settings{AirshipCTLSettings: rootSettings}
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: validateShort,
+		Long:  validateLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return validateSettings.Validate(cmd.OutOrStdout())
+		},
+	}
+
+	addValidateFlags(validateSettings, validateCmd)
+	return validateCmd
+}
+
+// addValidateFlags adds flags for document validate sub-command
+func addValidateFlags(settings *validate.Settings, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.StringVarP(
+		&settings.ClusterType,
+		"cluster-type",
+		"t",
+		config.Ephemeral,
+		"Select cluster type to validate documents for")
+
+	flags.StringVarP(
+		&settings.Phase,
+		"phase",
+		"p",
+		config.Initinfra,
+		"Select phase to validate documents for")
+}
@@ -0,0 +1,49 @@
+package phase
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+const (
+	applyExample = `# deploy documents of the initinfra phase
+airshipctl phase apply initinfra
+
+# simulate deployment of the phase
+airshipctl phase apply initinfra --dry-run`
+)
+
+// NewApplyCommand creates a command for deploying documents of a phase
+func NewApplyCommand(settings *phase.Settings) *cobra.Command {
+	applyCmd := &cobra.Command{
+		Use:     "apply PHASE_NAME",
+		Short:   "Deploy documents of the phase with its executor",
+		Example: applyExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return settings.Apply(args[0])
+		},
+	}
+
+	addApplyFlags(settings, applyCmd)
+	return applyCmd
+}
+
+// addApplyFlags adds flags for phase apply sub-command
+func addApplyFlags(settings *phase.Settings, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.BoolVar(
+		&settings.DryRun,
+		"dry-run",
+		false,
+		"Don't deliver documents to the cluster, simulate the changes instead")
+
+	flags.BoolVar(
+		&settings.Prune,
+		"prune",
+		false,
+		`If set to true, command will delete all kubernetes resources that are not`+
+			` defined in the phase documents and have airshipit.org/phase=<name> label`)
+}
@@ -0,0 +1,19 @@
+package phase
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+// NewListCommand creates a command for listing phases
+func NewListCommand(settings *phase.Settings) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List phases declared in the site manifests",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return settings.List(cmd.OutOrStdout())
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package phase
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+// NewPhaseCommand creates a command for interacting with phases
+func NewPhaseCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	phaseSettings := &phase.Settings{AirshipCTLSettings: rootSettings}
+	phaseRootCmd := &cobra.Command{
+		Use:   "phase",
+		Short: "Manage phases declared in the site manifests",
+	}
+
+	phaseRootCmd.AddCommand(NewListCommand(phaseSettings))
+	phaseRootCmd.AddCommand(NewRenderCommand(phaseSettings))
+	phaseRootCmd.AddCommand(NewApplyCommand(phaseSettings))
+
+	return phaseRootCmd
+}
@@ -0,0 +1,32 @@
+package phase_test
+
+import (
+	"testing"
+
+	"opendev.org/airship/airshipctl/cmd/phase"
+	pkgphase "opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestPhase(t *testing.T) {
+	tests := []*testutil.CmdTest{
+		{
+			Name:    "phase-with-defaults",
+			CmdLine: "",
+			Cmd:     phase.NewPhaseCommand(nil),
+		},
+		{
+			Name:    "phase-render-with-help",
+			CmdLine: "-h",
+			Cmd:     phase.NewRenderCommand(&pkgphase.Settings{}),
+		},
+		{
+			Name:    "phase-apply-with-help",
+			CmdLine: "-h",
+			Cmd:     phase.NewApplyCommand(&pkgphase.Settings{}),
+		},
+	}
+	for _, tt := range tests {
+		testutil.RunTest(t, tt)
+	}
+}
@@ -0,0 +1,37 @@
+package phase
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+// NewRenderCommand creates a command for rendering documents of a phase
+func NewRenderCommand(settings *phase.Settings) *cobra.Command {
+	renderCmd := &cobra.Command{
+		Use:   "render PHASE_NAME",
+		Short: "Render documents of the phase",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return settings.Render(args[0], cmd.OutOrStdout())
+		},
+	}
+
+	addRenderFlags(settings, renderCmd)
+	return renderCmd
+}
+
+// addRenderFlags adds flags for phase render sub-command
+func addRenderFlags(settings *phase.Settings, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.StringVarP(
+		&settings.Output,
+		"output",
+		"o",
+		document.YAMLFormat,
+		"Output format, one of: "+strings.Join(document.OutputFormats(), ", "))
+}
@@ -0,0 +1,19 @@
+package plan
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// NewPlanCommand creates a command for running phases in order
+func NewPlanCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	planRootCmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Run phases in the order of their dependencies",
+	}
+
+	planRootCmd.AddCommand(NewRunCommand(rootSettings))
+
+	return planRootCmd
+}
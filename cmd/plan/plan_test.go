@@ -0,0 +1,26 @@
+package plan_test
+
+import (
+	"testing"
+
+	"opendev.org/airship/airshipctl/cmd/plan"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestPlan(t *testing.T) {
+	tests := []*testutil.CmdTest{
+		{
+			Name:    "plan-with-defaults",
+			CmdLine: "",
+			Cmd:     plan.NewPlanCommand(nil),
+		},
+		{
+			Name:    "plan-run-with-help",
+			CmdLine: "-h",
+			Cmd:     plan.NewRunCommand(nil),
+		},
+	}
+	for _, tt := range tests {
+		testutil.RunTest(t, tt)
+	}
+}
@@ -0,0 +1,98 @@
+package plan
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/plan"
+)
+
+const (
+	runLong = `Apply phases declared in the site manifests in the order of their prerequisites.
+Every phase waits for its resources with status checks to become ready before
+the next phase starts. Completed phases are recorded in the state file and
+skipped by later runs, so a failed run can be resumed by running it again.`
+
+	runExample = `# run all phases
+airshipctl plan run
+
+# run phases up to and including controlplane
+airshipctl plan run --until controlplane
+
+# run all phases again ignoring phases completed by previous runs
+airshipctl plan run --reset`
+)
+
+// NewRunCommand creates a command for running phases in order
+func NewRunCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	runSettings := &plan.Settings{Settings: &phase.Settings{AirshipCTLSettings: rootSettings}}
+	runCmd := &cobra.Command{
+		Use:     "run",
+		Short:   "Apply phases in the order of their dependencies",
+		Long:    runLong,
+		Example: runExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSettings.Run(cmd.OutOrStdout())
+		},
+	}
+
+	addRunFlags(runSettings, runCmd)
+	return runCmd
+}
+
+// addRunFlags adds flags for plan run sub-command
+func addRunFlags(settings *plan.Settings, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.StringVar(
+		&settings.From,
+		"from",
+		"",
+		"Name of the first phase to run")
+
+	flags.StringVar(
+		&settings.Until,
+		"until",
+		"",
+		"Name of the last phase to run")
+
+	flags.StringVar(
+		&settings.StateFile,
+		"state-file",
+		"",
+		"Path of the file recording completed phases, defaults to "+
+			plan.StateDir+"/<current context>.yaml next to airship config")
+
+	flags.BoolVar(
+		&settings.Reset,
+		"reset",
+		false,
+		"Run phases completed by previous runs again")
+
+	flags.StringVar(
+		&settings.ReadyStatus,
+		"ready-status",
+		plan.DefaultReadyStatus,
+		"Status resources with status checks must reach before the next phase starts")
+
+	flags.DurationVar(
+		&settings.Timeout,
+		"timeout",
+		plan.DefaultTimeout,
+		"Time to wait for resources of each phase to become ready")
+
+	flags.BoolVar(
+		&settings.DryRun,
+		"dry-run",
+		false,
+		"Don't deliver documents to the cluster, simulate the changes instead")
+
+	flags.BoolVar(
+		&settings.Prune,
+		"prune",
+		false,
+		`If set to true, command will delete all kubernetes resources that are not`+
+			` defined in the phase documents and have airshipit.org/phase=<name> label`)
+}
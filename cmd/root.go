@@ -13,6 +13,8 @@ import (
 	"opendev.org/airship/airshipctl/cmd/completion"
 	"opendev.org/airship/airshipctl/cmd/config"
 	"opendev.org/airship/airshipctl/cmd/document"
+	"opendev.org/airship/airshipctl/cmd/phase"
+	"opendev.org/airship/airshipctl/cmd/plan"
 	"opendev.org/airship/airshipctl/cmd/remote"
 	"opendev.org/airship/airshipctl/cmd/secret"
 	"opendev.org/airship/airshipctl/pkg/environment"
@@ -57,8 +59,10 @@ func AddDefaultAirshipCTLCommands(cmd *cobra.Command, settings *environment.Airs
 	cmd.AddCommand(completion.NewCompletionCommand())
 	cmd.AddCommand(document.NewDocumentCommand(settings))
 	cmd.AddCommand(config.NewConfigCommand(settings))
+	cmd.AddCommand(phase.NewPhaseCommand(settings))
+	cmd.AddCommand(plan.NewPlanCommand(settings))
 	cmd.AddCommand(remote.NewRemoteCommand(settings))
-	cmd.AddCommand(secret.NewSecretCommand())
+	cmd.AddCommand(secret.NewSecretCommand(settings))
 
 	return cmd
 }
@@ -0,0 +1,33 @@
+package secret
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+const (
+	decryptLong = `Decrypt values of Secret documents in the file encrypted with the site key.
+Documents built by airshipctl are decrypted transparently if their kustomization
+lists a SecretDecryptor transformer, so the command is only needed to edit secrets.`
+
+	decryptExample = `# print decrypted secrets of the site
+airshipctl secret decrypt --src manifests/site/test-site/secrets.yaml`
+)
+
+// NewDecryptCommand creates a new command for decrypting secret documents
+func NewDecryptCommand() *cobra.Command {
+	decryptSettings := &secret.EncryptionSettings{}
+	decryptCmd := &cobra.Command{
+		Use:     "decrypt",
+		Short:   "Decrypt Secret documents encrypted with the site key",
+		Long:    decryptLong,
+		Example: decryptExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return decryptSettings.Decrypt(cmd.OutOrStdout())
+		},
+	}
+
+	addEncryptionFlags(decryptSettings, decryptCmd, "decrypt")
+	return decryptCmd
+}
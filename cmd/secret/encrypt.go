@@ -0,0 +1,57 @@
+package secret
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+const (
+	encryptLong = `Encrypt values of Secret documents in the file with the site key.
+The site key is read from the ` + secret.SiteKeyEnv + ` environment variable, from the file
+referenced by ` + secret.SiteKeyFileEnv + ` or from ~/.airship/` + secret.SiteKeyFile + `.
+Documents other than secrets and values which are already encrypted are left as is.`
+
+	encryptExample = `# encrypt secrets of the site in place
+airshipctl secret encrypt --src manifests/site/test-site/secrets.yaml --dst manifests/site/test-site/secrets.yaml`
+)
+
+// NewEncryptCommand creates a new command for encrypting secret documents
+func NewEncryptCommand() *cobra.Command {
+	encryptSettings := &secret.EncryptionSettings{}
+	encryptCmd := &cobra.Command{
+		Use:     "encrypt",
+		Short:   "Encrypt Secret documents with the site key",
+		Long:    encryptLong,
+		Example: encryptExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return encryptSettings.Encrypt(cmd.OutOrStdout())
+		},
+	}
+
+	addEncryptionFlags(encryptSettings, encryptCmd, "encrypt")
+	return encryptCmd
+}
+
+// addEncryptionFlags adds flags for secret encrypt and decrypt sub-commands
+func addEncryptionFlags(settings *secret.EncryptionSettings, cmd *cobra.Command, action string) {
+	flags := cmd.Flags()
+
+	flags.StringVar(
+		&settings.Src,
+		"src",
+		"",
+		"Path to the file with documents to "+action)
+
+	flags.StringVar(
+		&settings.Dst,
+		"dst",
+		"",
+		"Path to the file to write documents to, may be the same as --src; documents are printed if not set")
+
+	err := cmd.MarkFlagRequired("src")
+	if err != nil {
+		log.Fatal(err)
+	}
+}
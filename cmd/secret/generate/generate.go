@@ -1,9 +1,13 @@
 package generate
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
 
 // NewGenerateCommand creates a new command for generating secret information
-func NewGenerateCommand() *cobra.Command {
+func NewGenerateCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
 	generateRootCmd := &cobra.Command{
 		Use: "generate",
 		// TODO(howell): Make this more expressive
@@ -11,6 +15,8 @@ func NewGenerateCommand() *cobra.Command {
 	}
 
 	generateRootCmd.AddCommand(NewGenerateMasterPassphraseCommand())
+	generateRootCmd.AddCommand(NewGenerateSiteCommand(rootSettings))
+	generateRootCmd.AddCommand(NewGeneratePKICommand(rootSettings))
 
 	return generateRootCmd
 }
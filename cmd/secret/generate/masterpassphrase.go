@@ -8,18 +8,109 @@ import (
 	"opendev.org/airship/airshipctl/pkg/secret"
 )
 
+const (
+	masterPassphraseLong = `Generate a secure master passphrase. By default the passphrase has 24 characters
+with at least one lowercase letter, uppercase letter, number and symbol. Character
+classes, excluded characters and required counts of the classes can be changed, e.g.
+for BMCs rejecting some symbols. Word passphrases consist of words of the embedded
+wordlist joined by the separator.`
+
+	masterPassphraseExample = `# generate a passphrase without symbols rejected by the BMC
+airshipctl secret generate masterpassphrase --exclude '#&' --min-symbols 2
+
+# generate a passphrase of 8 words and print its entropy
+airshipctl secret generate masterpassphrase --words --length 8 --show-entropy`
+)
+
+// masterPassphraseOptions holds flags of secret generate masterpassphrase sub-command
+type masterPassphraseOptions struct {
+	policy      *secret.PassphrasePolicy
+	minCounts   map[string]*int
+	showEntropy bool
+}
+
 // NewGenerateMasterPassphraseCommand creates a new command for generating secret information
 func NewGenerateMasterPassphraseCommand() *cobra.Command {
+	o := &masterPassphraseOptions{policy: secret.NewPassphrasePolicy()}
 	masterPassphraseCmd := &cobra.Command{
 		Use: "masterpassphrase",
 		// TODO(howell): Make this more expressive
-		Short: "generates a secure master passphrase",
-		Run: func(cmd *cobra.Command, args []string) {
+		Short:   "generates a secure master passphrase",
+		Long:    masterPassphraseLong,
+		Example: masterPassphraseExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for class, count := range o.minCounts {
+				o.policy.MinCounts[class] = *count
+			}
+
 			engine := secret.NewPassphraseEngine(nil)
-			masterPassphrase := engine.GeneratePassphrase()
+			masterPassphrase, err := engine.GeneratePolicyPassphrase(o.policy)
+			if err != nil {
+				return err
+			}
 			fmt.Fprintln(cmd.OutOrStdout(), masterPassphrase)
+
+			if o.showEntropy {
+				entropy, err := o.policy.Entropy()
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Entropy: %.1f bits\n", entropy)
+			}
+			return nil
 		},
 	}
 
+	addMasterPassphraseFlags(o, masterPassphraseCmd)
 	return masterPassphraseCmd
 }
+
+// addMasterPassphraseFlags adds flags for secret generate masterpassphrase sub-command
+func addMasterPassphraseFlags(o *masterPassphraseOptions, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.IntVar(
+		&o.policy.Length,
+		"length",
+		0,
+		fmt.Sprintf("Number of characters of the passphrase or number of words if --words is set; "+
+			"24 characters or %d words if not set", secret.DefaultWordCount))
+
+	flags.StringSliceVar(
+		&o.policy.Classes,
+		"classes",
+		o.policy.Classes,
+		"Character classes of the passphrase")
+
+	flags.StringVar(
+		&o.policy.Exclude,
+		"exclude",
+		"",
+		"Characters never used in the passphrase")
+
+	o.minCounts = make(map[string]*int, len(secret.Classes))
+	for _, class := range secret.Classes {
+		o.minCounts[class] = flags.Int(
+			"min-"+class,
+			o.policy.MinCounts[class],
+			fmt.Sprintf("Minimal number of %s in the passphrase, ignored if the class is not allowed", class))
+	}
+
+	flags.BoolVar(
+		&o.policy.Words,
+		"words",
+		false,
+		"Generate a passphrase of words instead of characters")
+
+	flags.StringVar(
+		&o.policy.Separator,
+		"separator",
+		secret.DefaultWordSeparator,
+		"Separator of words of the passphrase")
+
+	flags.BoolVar(
+		&o.showEntropy,
+		"show-entropy",
+		false,
+		"Print entropy bits of the passphrase to stderr")
+}
@@ -0,0 +1,79 @@
+package generate
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/pki"
+)
+
+const (
+	pkiLong = `Generate certificate authorities, service account keypair and admin client certificate
+of the cluster as Secret documents and add the cluster and its admin user to the airship
+config. Credentials found in the --dst file are kept as is.`
+
+	pkiExample = `# generate credentials of the target cluster
+airshipctl secret generate pki target-cluster --cluster-type target --server https://10.23.25.101:6443 \
+  --dst manifests/site/test-site/target/pki.yaml --encrypt`
+)
+
+// NewGeneratePKICommand creates a new command for generating credentials of a cluster
+func NewGeneratePKICommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	pkiSettings := &pki.Settings{AirshipCTLSettings: rootSettings}
+	pkiCmd := &cobra.Command{
+		Use:     "pki CLUSTER_NAME",
+		Short:   "Generate credentials of the cluster and add it to the airship config",
+		Long:    pkiLong,
+		Example: pkiExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pkiSettings.ClusterName = args[0]
+			return pkiSettings.Generate(cmd.OutOrStdout())
+		},
+	}
+
+	addGeneratePKIFlags(pkiSettings, pkiCmd)
+	return pkiCmd
+}
+
+// addGeneratePKIFlags adds flags for secret generate pki sub-command
+func addGeneratePKIFlags(settings *pki.Settings, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.StringVar(
+		&settings.ClusterType,
+		config.FlagClusterType,
+		config.Target,
+		"Type of the cluster")
+
+	flags.StringVar(
+		&settings.Server,
+		config.FlagAPIServer,
+		"",
+		"Address of the API server of the cluster")
+
+	flags.StringVar(
+		&settings.Namespace,
+		config.FlagNamespace,
+		"",
+		"Namespace of generated Secret documents")
+
+	flags.StringVar(
+		&settings.Dst,
+		"dst",
+		"",
+		"Path to the file to write documents to, credentials found in the file are kept")
+
+	flags.BoolVar(
+		&settings.Encrypt,
+		"encrypt",
+		false,
+		"Encrypt generated documents with the site key")
+
+	err := cmd.MarkFlagRequired("dst")
+	if err != nil {
+		log.Fatal(err)
+	}
+}
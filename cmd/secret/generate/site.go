@@ -0,0 +1,54 @@
+package generate
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/secret"
+	"opendev.org/airship/airshipctl/pkg/secret/site"
+)
+
+const (
+	siteLong = `Generate secrets declared by ` + secret.SiteSecretsKind + ` documents of the site manifests
+as Secret documents. Passphrases, SSH keypairs, certificate authorities, certificates
+signed by them and kubeadm tokens are supported. Secrets found in the --dst file are
+kept as is, so that values generated by previous runs stay stable.`
+
+	siteExample = `# generate site secrets encrypted with the site key
+airshipctl secret generate site --dst manifests/site/test-site/secrets.yaml --encrypt`
+)
+
+// NewGenerateSiteCommand creates a new command for generating secrets of the site
+func NewGenerateSiteCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	generateSettings := &site.Settings{AirshipCTLSettings: rootSettings}
+	siteCmd := &cobra.Command{
+		Use:     "site",
+		Short:   "Generate secrets declared in the site manifests",
+		Long:    siteLong,
+		Example: siteExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return generateSettings.Generate(cmd.OutOrStdout())
+		},
+	}
+
+	addGenerateSiteFlags(generateSettings, siteCmd)
+	return siteCmd
+}
+
+// addGenerateSiteFlags adds flags for secret generate site sub-command
+func addGenerateSiteFlags(settings *site.Settings, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.StringVar(
+		&settings.Dst,
+		"dst",
+		"",
+		"Path to the file to write documents to, secrets found in the file are kept; documents are printed if not set")
+
+	flags.BoolVar(
+		&settings.Encrypt,
+		"encrypt",
+		false,
+		"Encrypt generated documents with the site key")
+}
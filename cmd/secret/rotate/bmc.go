@@ -0,0 +1,111 @@
+package rotate
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/secret"
+	"opendev.org/airship/airshipctl/pkg/secret/rotate"
+)
+
+const (
+	bmcLong = `Rotate BMC passwords of BareMetalHosts of the cluster type. For every host a new password
+is generated and set through the Redfish AccountService, the host must accept the new
+password before the credentials Secret in --secrets-file is updated. If it doesn't, the
+previous password is restored and the file is left as is. Hosts sharing a credentials
+Secret get the same password. Encrypted passwords are encrypted with the site key.`
+
+	bmcExample = `# rotate the BMC password of a single host
+airshipctl secret rotate bmc --host node01 --secrets-file manifests/site/test-site/target/bmc-secrets.yaml
+
+# rotate BMC passwords of all hosts without symbols rejected by the BMCs
+airshipctl secret rotate bmc --all --exclude '#&' \
+  --secrets-file manifests/site/test-site/target/bmc-secrets.yaml`
+)
+
+// NewRotateBMCCommand creates a new command for rotating BMC passwords
+func NewRotateBMCCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	bmcSettings := &rotate.BMCSettings{
+		AirshipCTLSettings: rootSettings,
+		Policy:             secret.NewPassphrasePolicy(),
+	}
+	bmcCmd := &cobra.Command{
+		Use:     "bmc",
+		Short:   "Rotate BMC passwords of BareMetalHosts",
+		Long:    bmcLong,
+		Example: bmcExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return bmcSettings.Rotate(cmd.OutOrStdout())
+		},
+	}
+
+	addRotateBMCFlags(bmcSettings, bmcCmd)
+	return bmcCmd
+}
+
+// addRotateBMCFlags adds flags for secret rotate bmc sub-command
+func addRotateBMCFlags(settings *rotate.BMCSettings, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.StringVar(
+		&settings.ClusterType,
+		config.FlagClusterType,
+		config.Target,
+		"Type of the cluster whose BareMetalHosts are selected")
+
+	flags.StringSliceVar(
+		&settings.Hosts,
+		"host",
+		nil,
+		"Name of the BareMetalHost to rotate the password of, may be repeated")
+
+	flags.BoolVar(
+		&settings.All,
+		"all",
+		false,
+		"Rotate passwords of all BareMetalHosts")
+
+	flags.StringVar(
+		&settings.SecretsFile,
+		"secrets-file",
+		"",
+		"Path to the manifest file holding BMC credentials secrets")
+
+	flags.IntVar(
+		&settings.Policy.Length,
+		"length",
+		0,
+		"Number of characters of new passwords, 24 if not set")
+
+	flags.StringSliceVar(
+		&settings.Policy.Classes,
+		"classes",
+		settings.Policy.Classes,
+		"Character classes of new passwords")
+
+	flags.StringVar(
+		&settings.Policy.Exclude,
+		"exclude",
+		"",
+		"Characters never used in new passwords")
+
+	flags.BoolVar(
+		&settings.Insecure,
+		"insecure",
+		false,
+		"Skip verification of BMC certificates")
+
+	flags.BoolVar(
+		&settings.UseProxy,
+		"use-proxy",
+		false,
+		"Send requests to BMCs through the proxy of the environment")
+
+	err := cmd.MarkFlagRequired("secrets-file")
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,19 @@
+package rotate
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// NewRotateCommand creates a new command for rotating secrets
+func NewRotateCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	rotateRootCmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Rotate credentials of the site",
+	}
+
+	rotateRootCmd.AddCommand(NewRotateBMCCommand(rootSettings))
+
+	return rotateRootCmd
+}
@@ -4,17 +4,22 @@ import (
 	"github.com/spf13/cobra"
 
 	"opendev.org/airship/airshipctl/cmd/secret/generate"
+	"opendev.org/airship/airshipctl/cmd/secret/rotate"
+	"opendev.org/airship/airshipctl/pkg/environment"
 )
 
 // NewSecretCommand creates a new command for managing airshipctl secrets
-func NewSecretCommand() *cobra.Command {
+func NewSecretCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
 	secretRootCmd := &cobra.Command{
 		Use: "secret",
 		// TODO(howell): Make this more expressive
 		Short: "manages secrets",
 	}
 
-	secretRootCmd.AddCommand(generate.NewGenerateCommand())
+	secretRootCmd.AddCommand(generate.NewGenerateCommand(rootSettings))
+	secretRootCmd.AddCommand(NewEncryptCommand())
+	secretRootCmd.AddCommand(NewDecryptCommand())
+	secretRootCmd.AddCommand(rotate.NewRotateCommand(rootSettings))
 
 	return secretRootCmd
 }
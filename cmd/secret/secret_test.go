@@ -0,0 +1,54 @@
+package secret_test
+
+import (
+	"testing"
+
+	"opendev.org/airship/airshipctl/cmd/secret"
+	"opendev.org/airship/airshipctl/cmd/secret/generate"
+	"opendev.org/airship/airshipctl/cmd/secret/rotate"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestSecret(t *testing.T) {
+	tests := []*testutil.CmdTest{
+		{
+			Name:    "secret-with-defaults",
+			CmdLine: "",
+			Cmd:     secret.NewSecretCommand(&environment.AirshipCTLSettings{}),
+		},
+		{
+			Name:    "secret-encrypt-with-help",
+			CmdLine: "-h",
+			Cmd:     secret.NewEncryptCommand(),
+		},
+		{
+			Name:    "secret-decrypt-with-help",
+			CmdLine: "-h",
+			Cmd:     secret.NewDecryptCommand(),
+		},
+		{
+			Name:    "secret-generate-masterpassphrase-with-help",
+			CmdLine: "-h",
+			Cmd:     generate.NewGenerateMasterPassphraseCommand(),
+		},
+		{
+			Name:    "secret-generate-site-with-help",
+			CmdLine: "-h",
+			Cmd:     generate.NewGenerateSiteCommand(&environment.AirshipCTLSettings{}),
+		},
+		{
+			Name:    "secret-generate-pki-with-help",
+			CmdLine: "-h",
+			Cmd:     generate.NewGeneratePKICommand(&environment.AirshipCTLSettings{}),
+		},
+		{
+			Name:    "secret-rotate-bmc-with-help",
+			CmdLine: "-h",
+			Cmd:     rotate.NewRotateBMCCommand(&environment.AirshipCTLSettings{}),
+		},
+	}
+	for _, tt := range tests {
+		testutil.RunTest(t, tt)
+	}
+}
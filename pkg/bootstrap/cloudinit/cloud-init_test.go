@@ -8,6 +8,8 @@ import (
 	"sigs.k8s.io/kustomize/v3/pkg/types"
 
 	"opendev.org/airship/airshipctl/pkg/document"
+	// the bundle built from testdata doesn't use the bundle cache of the user
+	_ "opendev.org/airship/airshipctl/testutil"
 )
 
 func TestGetCloudData(t *testing.T) {
@@ -0,0 +1,257 @@
+package initinfra
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/diff"
+)
+
+// Actions reported for every document by Diff
+const (
+	ActionCreated    = "created"
+	ActionConfigured = "configured"
+	ActionUnchanged  = "unchanged"
+	// ActionNotValidated is reported for documents the server can't dry run
+	// before other documents of the phase are deployed, such as custom
+	// resources whose definitions are in the same phase or objects in a
+	// namespace created by the phase
+	ActionNotValidated = "created (not validated)"
+	// ActionFailed is reported for documents the server rejects, such as
+	// invalid objects or objects denied by admission
+	ActionFailed = "failed"
+)
+
+// ignoredFields are set by the API server and excluded from the patches
+var ignoredFields = [][]string{
+	{"metadata", "creationTimestamp"},
+	{"metadata", "generation"},
+	{"metadata", "managedFields"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "selfLink"},
+	{"metadata", "uid"},
+	{"status"},
+}
+
+// Change describes what deploying the document would do to the live object
+type Change struct {
+	ID     diff.ID
+	Action string
+	// Patch is a JSON merge patch from the live object to the object
+	// returned by the server side dry run
+	Patch []byte
+	// Reason is why the document was not validated or failed
+	Reason string
+}
+
+// Diff compares documents of the phase with the live objects of the cluster
+// and writes to out the patches the server would apply when deploying them.
+// Every document is submitted to the API server in dry run mode, so
+// defaulting, admission and validation are taken into account. Documents
+// the server rejects are reported along with the rest, the error tells how
+// many of them there are
+func (infra *Infra) Diff(out io.Writer) error {
+	docs, err := infra.documents()
+	if err != nil {
+		return err
+	}
+
+	changes, err := infra.Changes(docs)
+	if err != nil {
+		return err
+	}
+	if err = WriteChanges(out, changes); err != nil {
+		return err
+	}
+	failed := 0
+	for _, change := range changes {
+		if change.Action == ActionFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return ErrDiffFailed{Documents: failed}
+	}
+	return nil
+}
+
+// Changes returns changes the server would make to the cluster for every document
+func (infra *Infra) Changes(docs []document.Document) ([]Change, error) {
+	groupResources, err := restmapper.GetAPIGroupResources(infra.Client.ClientSet().Discovery())
+	if err != nil {
+		return nil, err
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+	dynamicClient := infra.Client.DynamicClient()
+
+	changes := make([]Change, 0, len(docs))
+	for _, doc := range docs {
+		change, err := dryRunDocument(dynamicClient, mapper, doc)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func dryRunDocument(client dynamic.Interface, mapper meta.RESTMapper, doc document.Document) (Change, error) {
+	id, err := diff.NewID(doc)
+	if err != nil {
+		return Change{}, err
+	}
+	change := Change{ID: id}
+
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return change, err
+	}
+	obj := &unstructured.Unstructured{}
+	if err = obj.UnmarshalJSON(data); err != nil {
+		return change, err
+	}
+
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		return notValidated(change, obj, err)
+	}
+	if err != nil {
+		return failed(change, err), nil
+	}
+	var resource dynamic.ResourceInterface = client.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		namespace := obj.GetNamespace()
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+		resource = client.Resource(mapping.Resource).Namespace(namespace)
+	}
+
+	dryRun := []string{metav1.DryRunAll}
+	live, err := resource.Get(obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		created, createErr := resource.Create(obj, metav1.CreateOptions{DryRun: dryRun})
+		// the namespace of the object may be created by the phase
+		if apierrors.IsNotFound(createErr) {
+			return notValidated(change, obj, createErr)
+		}
+		if createErr != nil {
+			return failed(change, createErr), nil
+		}
+		change.Action = ActionCreated
+		change.Patch, err = json.Marshal(stripIgnoredFields(created))
+		return change, err
+	}
+	if err != nil {
+		return failed(change, err), nil
+	}
+
+	merged, err := resource.Patch(obj.GetName(), types.MergePatchType, data, metav1.PatchOptions{DryRun: dryRun})
+	if err != nil {
+		return failed(change, err), nil
+	}
+	liveData, err := json.Marshal(stripIgnoredFields(live))
+	if err != nil {
+		return change, err
+	}
+	mergedData, err := json.Marshal(stripIgnoredFields(merged))
+	if err != nil {
+		return change, err
+	}
+	change.Patch, err = jsonpatch.CreateMergePatch(liveData, mergedData)
+	if err != nil {
+		return change, err
+	}
+
+	change.Action = ActionConfigured
+	if string(change.Patch) == "{}" {
+		change.Action = ActionUnchanged
+		change.Patch = nil
+	}
+	return change, nil
+}
+
+// notValidated reports the document as created along with the reason the
+// server could not dry run it, the patch is the document itself
+func notValidated(change Change, obj *unstructured.Unstructured, reason error) (Change, error) {
+	change.Action = ActionNotValidated
+	change.Reason = reason.Error()
+	var err error
+	change.Patch, err = json.Marshal(stripIgnoredFields(obj))
+	return change, err
+}
+
+// failed reports the document as rejected by the server with the reason
+func failed(change Change, reason error) Change {
+	change.Action = ActionFailed
+	change.Reason = reason.Error()
+	return change
+}
+
+func stripIgnoredFields(obj *unstructured.Unstructured) map[string]interface{} {
+	content := obj.DeepCopy().UnstructuredContent()
+	for _, field := range ignoredFields {
+		unstructured.RemoveNestedField(content, field...)
+	}
+	return content
+}
+
+// WriteChanges writes every change followed by its patch in YAML form and a
+// summary line to out
+func WriteChanges(out io.Writer, changes []Change) error {
+	counts := map[string]int{}
+	for _, change := range changes {
+		counts[change.Action]++
+		if _, err := fmt.Fprintf(out, "%s %s\n", change.ID, change.Action); err != nil {
+			return err
+		}
+		if change.Reason != "" {
+			if _, err := fmt.Fprintf(out, "    # %s\n", change.Reason); err != nil {
+				return err
+			}
+		}
+		if len(change.Patch) == 0 {
+			continue
+		}
+		patch, err := yaml.JSONToYAML(change.Patch)
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(string(patch), "\n"), "\n") {
+			if _, err = fmt.Fprintf(out, "    %s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(out, "%d %s, %d %s, %d %s",
+		counts[ActionCreated], ActionCreated,
+		counts[ActionConfigured], ActionConfigured,
+		counts[ActionUnchanged], ActionUnchanged)
+	if err != nil {
+		return err
+	}
+	for _, action := range []string{ActionNotValidated, ActionFailed} {
+		if counts[action] == 0 {
+			continue
+		}
+		if _, err = fmt.Fprintf(out, ", %d %s", counts[action], action); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintln(out)
+	return err
+}
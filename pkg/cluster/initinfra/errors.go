@@ -0,0 +1,13 @@
+package initinfra
+
+import "fmt"
+
+// ErrDiffFailed returned if the server rejects any of the documents of the
+// diff, the documents are reported as failed along with the reasons
+type ErrDiffFailed struct {
+	Documents int
+}
+
+func (e ErrDiffFailed) Error() string {
+	return fmt.Sprintf("The server rejected %d documents", e.Documents)
+}
@@ -1,8 +1,11 @@
 package initinfra
 
 import (
+	"io"
+
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/policy"
 	"opendev.org/airship/airshipctl/pkg/environment"
 	"opendev.org/airship/airshipctl/pkg/k8s/client"
 )
@@ -15,6 +18,7 @@ type Infra struct {
 
 	DryRun      bool
 	Prune       bool
+	ShowDiff    bool
 	ClusterType string
 }
 
@@ -25,14 +29,18 @@ func NewInfra(rs *environment.AirshipCTLSettings) *Infra {
 	return infra
 }
 
-// Run intinfra subcommand logic
-func (infra *Infra) Run() error {
+// Run intinfra subcommand logic, if ShowDiff is set changes are written
+// to out instead of being deployed
+func (infra *Infra) Run(out io.Writer) error {
 	infra.FileSystem = document.NewDocumentFs()
 	var err error
 	infra.Client, err = client.NewClient(infra.RootSettings)
 	if err != nil {
 		return err
 	}
+	if infra.ShowDiff {
+		return infra.Diff(out)
+	}
 	return infra.Deploy()
 }
 
@@ -50,29 +58,43 @@ func (infra *Infra) Deploy() error {
 		ao.SetPrune(document.InitInfraSelector)
 	}
 
-	globalConf := infra.RootSettings.Config()
-	if err = globalConf.EnsureComplete(); err != nil {
+	docs, err := infra.documents()
+	if err != nil {
 		return err
 	}
 
+	return kctl.Apply(docs, ao)
+}
+
+// documents returns documents of the initinfra phase to be deployed to the cluster
+func (infra *Infra) documents() ([]document.Document, error) {
+	globalConf := infra.RootSettings.Config()
+	if err := globalConf.EnsureComplete(); err != nil {
+		return nil, err
+	}
+
 	kustomizePath, err := globalConf.CurrentContextEntryPoint(infra.ClusterType, config.Initinfra)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	b, err := document.NewBundleByPath(kustomizePath)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// Documents violating policy rules of the bundle are never deployed
+	if err = policy.Enforce(b); err != nil {
+		return nil, err
 	}
 
 	// Returns all documents for this phase
 	docs, err := b.Select(document.NewDeployToK8sSelector())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(docs) == 0 {
-		return document.ErrDocNotFound{}
+		return nil, document.ErrDocNotFound{}
 	}
-
-	return kctl.Apply(docs, ao)
+	return docs, nil
 }
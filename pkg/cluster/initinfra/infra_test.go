@@ -1,12 +1,24 @@
 package initinfra_test
 
 import (
+	"bytes"
 	"errors"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+	dynamic_fake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	kubernetes_fake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"opendev.org/airship/airshipctl/pkg/cluster/initinfra"
 	"opendev.org/airship/airshipctl/pkg/document"
@@ -14,6 +26,8 @@ import (
 	"opendev.org/airship/airshipctl/pkg/k8s/client"
 	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
 	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	// bundles built by deploy and diff don't use the bundle cache of the user
+	_ "opendev.org/airship/airshipctl/testutil"
 	"opendev.org/airship/airshipctl/testutil/k8sutils"
 )
 
@@ -86,6 +100,160 @@ func TestDeploy(t *testing.T) {
 	}
 }
 
+func TestDiff(t *testing.T) {
+	rs := makeNewFakeRootSettings(t, kubeconfigPath, airshipConfigFile)
+
+	tests := []struct {
+		name        string
+		live        []runtime.Object
+		unknownKind bool
+		createErr   error
+		patchErr    error
+		expected    string
+		expectedErr error
+	}{
+		{
+			name: "created",
+			expected: `v1 ReplicationController test/test-rc created
+    apiVersion: v1
+    kind: ReplicationController
+`,
+		},
+		{
+			name: "configured",
+			live: []runtime.Object{makeRC(3)},
+			expected: `v1 ReplicationController test/test-rc configured
+    spec:
+      replicas: 1
+0 created, 1 configured, 0 unchanged
+`,
+		},
+		{
+			name: "unchanged",
+			live: []runtime.Object{makeRC(1)},
+			expected: `v1 ReplicationController test/test-rc unchanged
+0 created, 0 configured, 1 unchanged
+`,
+		},
+		{
+			name:      "missing-namespace",
+			createErr: apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "test"),
+			expected: `v1 ReplicationController test/test-rc created (not validated)
+    # namespaces "test" not found
+    apiVersion: v1
+    kind: ReplicationController
+`,
+		},
+		{
+			name:        "unknown-kind",
+			unknownKind: true,
+			expected:    "v1 ReplicationController test/test-rc created (not validated)\n",
+		},
+		{
+			name: "invalid",
+			createErr: apierrors.NewInvalid(schema.GroupKind{Kind: "ReplicationController"}, "test-rc",
+				field.ErrorList{field.Required(field.NewPath("spec", "selector"), "")}),
+			expected: `v1 ReplicationController test/test-rc failed
+    # ReplicationController "test-rc" is invalid: spec.selector: Required value
+0 created, 0 configured, 0 unchanged, 1 failed
+`,
+			expectedErr: initinfra.ErrDiffFailed{Documents: 1},
+		},
+		{
+			name: "patch-failure",
+			live: []runtime.Object{makeRC(3)},
+			patchErr: apierrors.NewForbidden(schema.GroupResource{Resource: "replicationcontrollers"}, "test-rc",
+				errors.New("denied by webhook")),
+			expected: `v1 ReplicationController test/test-rc failed
+    # replicationcontrollers "test-rc" is forbidden: denied by webhook
+0 created, 0 configured, 0 unchanged, 1 failed
+`,
+			expectedErr: initinfra.ErrDiffFailed{Documents: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			infra := initinfra.NewInfra(rs)
+			infra.ClusterType = "ephemeral"
+			resources := rcResources
+			if tt.unknownKind {
+				resources = nil
+			}
+			infra.Client = makeDiffClient(resources, tt.createErr, tt.patchErr, tt.live...)
+
+			out := &bytes.Buffer{}
+			assert.Equal(t, tt.expectedErr, infra.Diff(out))
+			assert.Contains(t, out.String(), tt.expected)
+			if tt.expectedErr == nil && (tt.createErr != nil || tt.unknownKind) {
+				assert.Contains(t, out.String(), "0 created, 0 configured, 0 unchanged, 1 created (not validated)\n")
+			}
+		})
+	}
+}
+
+// rcResources are the API resources discovered by the diff client
+var rcResources = []metav1.APIResource{
+	{Name: "replicationcontrollers", Namespaced: true, Kind: "ReplicationController"},
+}
+
+func makeDiffClient(resources []metav1.APIResource, createErr, patchErr error, obj ...runtime.Object) fake.Client {
+	clientSet := kubernetes_fake.NewSimpleClientset()
+	clientSet.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: resources,
+		},
+	}
+	dynamicClient := dynamic_fake.NewSimpleDynamicClient(runtime.NewScheme(), obj...)
+	if createErr != nil {
+		dynamicClient.PrependReactor("create", "*", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, createErr
+		})
+	}
+	if patchErr != nil {
+		dynamicClient.PrependReactor("patch", "*", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, patchErr
+		})
+	}
+	return fake.Client{
+		MockClientSet:     func() kubernetes.Interface { return clientSet },
+		MockDynamicClient: func() dynamic.Interface { return dynamicClient },
+	}
+}
+
+// makeRC returns live replication controller matching the one in testdata
+func makeRC(replicas int64) *unstructured.Unstructured {
+	container := map[string]interface{}{
+		"name":  "test-rc",
+		"image": "nginx",
+		"ports": []interface{}{map[string]interface{}{"containerPort": int64(80)}},
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ReplicationController",
+			"metadata": map[string]interface{}{
+				"name":      "test-rc",
+				"namespace": "test",
+				"labels":    map[string]interface{}{"airshipit.org/phase": "initinfra"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"labels": map[string]interface{}{"name": "test-rc"},
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{container},
+					},
+				},
+			},
+		},
+	}
+}
+
 // MakeNewFakeRootSettings takes kubeconfig path and directory path to fixture dir as argument.
 func makeNewFakeRootSettings(t *testing.T, kp string, dir string) *environment.AirshipCTLSettings {
 	t.Helper()
@@ -632,6 +632,13 @@ func (c *Config) CurrentContextEntryPoint(clusterType string, phase string) (str
 	if err != nil {
 		return "", err
 	}
+	return c.CurrentContextBundlePath(path.Join(clusterType, phase))
+}
+
+// CurrentContextBundlePath returns path to build bundle located at bundlePath
+// relative to the sub path of the current context manifest
+// example CurrentContextBundlePath("phases")
+func (c *Config) CurrentContextBundlePath(bundlePath string) (string, error) {
 	ccm, err := c.CurrentContextManifest()
 	if err != nil {
 		return "", err
@@ -643,8 +650,7 @@ func (c *Config) CurrentContextEntryPoint(clusterType string, phase string) (str
 	return path.Join(
 		ccm.TargetPath,
 		ccm.SubPath,
-		clusterType,
-		phase), nil
+		bundlePath), nil
 }
 
 // Credential or AuthInfo related methods
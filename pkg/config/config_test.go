@@ -19,6 +19,7 @@ package config_test
 import (
 	"fmt"
 	"os"
+	"path"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -555,6 +556,22 @@ func TestCurrentContextEntryPoint(t *testing.T) {
 	assert.Nil(t, nil, entryPoint)
 }
 
+func TestCurrentContextBundlePath(t *testing.T) {
+	conf, cleanup := testutil.InitConfig(t)
+	defer cleanup(t)
+
+	_, err := conf.CurrentContextBundlePath("phases")
+	require.Error(t, err)
+
+	conf.CurrentContext = currentContextName
+	conf.Contexts[currentContextName].Manifest = defaultString
+
+	manifest := conf.Manifests[defaultString]
+	bundlePath, err := conf.CurrentContextBundlePath("phases")
+	require.NoError(t, err)
+	assert.Equal(t, path.Join(manifest.TargetPath, manifest.SubPath, "phases"), bundlePath)
+}
+
 // AuthInfo Related
 
 func TestGetAuthInfos(t *testing.T) {
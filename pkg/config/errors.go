@@ -47,7 +47,36 @@ type ErrMutuallyExclusiveCheckout struct {
 }
 
 func (e ErrMutuallyExclusiveCheckout) Error() string {
-	return "Chekout mutually execlusive, use either: commit-hash, branch or tag"
+	return "Chekout mutually execlusive, use either: commit-hash, branch, tag or remote-ref"
+}
+
+// ErrInvalidRemoteRef is returned if the remote ref of the checkout options
+// is not a full reference name
+type ErrInvalidRemoteRef struct {
+	RemoteRef string
+}
+
+func (e ErrInvalidRemoteRef) Error() string {
+	return fmt.Sprintf("Remote ref %q must be a full reference name, e.g. refs/changes/04/691202/5", e.RemoteRef)
+}
+
+// ErrInvalidCloneDepth is returned if the clone depth is negative
+type ErrInvalidCloneDepth struct {
+	Depth int
+}
+
+func (e ErrInvalidCloneDepth) Error() string {
+	return fmt.Sprintf("Clone depth %d must not be negative", e.Depth)
+}
+
+// ErrInvalidSparsePath is returned if the sparse path is empty or is not
+// within the repository
+type ErrInvalidSparsePath struct {
+	Path string
+}
+
+func (e ErrInvalidSparsePath) Error() string {
+	return fmt.Sprintf("Sparse path %q must be a path relative to the repository root", e.Path)
 }
 
 // ErrBootstrapInfoNotFound returned if bootstrap
@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	"gopkg.in/src-d/go-git.v4"
+	gitconfig "gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/plumbing"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
@@ -17,6 +20,9 @@ const (
 	SSHAuth   = "ssh-key"
 	SSHPass   = "ssh-pass"
 	HTTPBasic = "http-basic"
+
+	// FetchHead is the reference remote refs are fetched to and checked out from
+	FetchHead = plumbing.ReferenceName("FETCH_HEAD")
 )
 
 // RepoCheckout methods
@@ -40,8 +46,31 @@ func (c *RepoCheckout) Validate() error {
 	if count > 1 {
 		return ErrMutuallyExclusiveCheckout{}
 	}
-	if c.RemoteRef != "" {
-		return fmt.Errorf("Repository checkout by RemoteRef is not yet implemented\n%w", errors.ErrNotImplemented{})
+	if c.RemoteRef != "" && !strings.HasPrefix(c.RemoteRef, "refs/") {
+		return ErrInvalidRemoteRef{RemoteRef: c.RemoteRef}
+	}
+	return nil
+}
+
+// RepoClone methods
+
+func (c *RepoClone) String() string {
+	yaml, err := yaml.Marshal(&c)
+	if err != nil {
+		return ""
+	}
+	return string(yaml)
+}
+
+func (c *RepoClone) Validate() error {
+	if c.Depth < 0 {
+		return ErrInvalidCloneDepth{Depth: c.Depth}
+	}
+	for _, p := range c.SparsePaths {
+		cleaned := path.Clean(p)
+		if p == "" || path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return ErrInvalidSparsePath{Path: p}
+		}
 	}
 	return nil
 }
@@ -119,6 +148,13 @@ func (repo *Repository) Validate() error {
 		}
 	}
 
+	if repo.CloneOptions != nil {
+		err := repo.CloneOptions.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -138,33 +174,80 @@ func (repo *Repository) ToAuth() (transport.AuthMethod, error) {
 	}
 }
 
+// ToCheckoutOptions returns options checking out the commit hash, branch, tag
+// or remote ref. The commit hash takes precedence so that other options can
+// be pinned to the commit they have been resolved to, e.g. by a lock file
 func (repo *Repository) ToCheckoutOptions(force bool) *git.CheckoutOptions {
 	co := &git.CheckoutOptions{
 		Force: force,
 	}
 	switch {
 	case repo.CheckoutOptions == nil:
+	case repo.CheckoutOptions.CommitHash != "":
+		co.Hash = plumbing.NewHash(repo.CheckoutOptions.CommitHash)
 	case repo.CheckoutOptions.Branch != "":
 		co.Branch = plumbing.NewBranchReferenceName(repo.CheckoutOptions.Branch)
 	case repo.CheckoutOptions.Tag != "":
 		co.Branch = plumbing.NewTagReferenceName(repo.CheckoutOptions.Tag)
-	case repo.CheckoutOptions.CommitHash != "":
-		co.Hash = plumbing.NewHash(repo.CheckoutOptions.CommitHash)
+	case repo.CheckoutOptions.RemoteRef != "":
+		// HEAD is detached at the fetched commit since FETCH_HEAD is not a branch
+		co.Branch = FetchHead
 	}
 	return co
 }
 
+// ToCloneOptions returns options cloning history limited by clone options,
+// only the branch or tag of checkout options is cloned for single branch
+// clones. Clone doesn't check out sparse clones since it writes whole trees
 func (repo *Repository) ToCloneOptions(auth transport.AuthMethod) *git.CloneOptions {
-	return &git.CloneOptions{
+	co := &git.CloneOptions{
 		Auth: auth,
 		URL:  repo.URLString,
 	}
+	if repo.CloneOptions == nil {
+		return co
+	}
+	co.Depth = repo.CloneOptions.Depth
+	co.NoCheckout = len(repo.CloneOptions.SparsePaths) > 0
+	if repo.CloneOptions.SingleBranch {
+		co.SingleBranch = true
+		if repo.CheckoutOptions != nil {
+			switch {
+			case repo.CheckoutOptions.Branch != "":
+				co.ReferenceName = plumbing.NewBranchReferenceName(repo.CheckoutOptions.Branch)
+			case repo.CheckoutOptions.Tag != "":
+				co.ReferenceName = plumbing.NewTagReferenceName(repo.CheckoutOptions.Tag)
+			}
+		}
+	}
+	return co
 }
 
+// ToFetchOptions returns options fetching the remote ref to FetchHead if it
+// is set, default refs are fetched otherwise. Single branch clones keep
+// fetching their branch only since clone limits refs of the remote
 func (repo *Repository) ToFetchOptions(auth transport.AuthMethod) *git.FetchOptions {
-	return &git.FetchOptions{Auth: auth}
+	fo := &git.FetchOptions{Auth: auth}
+	if repo.CloneOptions != nil {
+		fo.Depth = repo.CloneOptions.Depth
+	}
+	if repo.CheckoutOptions != nil && repo.CheckoutOptions.RemoteRef != "" {
+		fo.RefSpecs = []gitconfig.RefSpec{
+			gitconfig.RefSpec(fmt.Sprintf("+%s:%s", repo.CheckoutOptions.RemoteRef, FetchHead)),
+		}
+	}
+	return fo
 }
 
 func (repo *Repository) URL() string {
 	return repo.URLString
 }
+
+// ToSparsePaths returns paths the working tree is limited to, the whole tree
+// is checked out if there are none
+func (repo *Repository) ToSparsePaths() []string {
+	if repo.CloneOptions == nil {
+		return nil
+	}
+	return repo.CloneOptions.SparsePaths
+}
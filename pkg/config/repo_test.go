@@ -3,6 +3,7 @@ package config_test
 import (
 	"testing"
 
+	"gopkg.in/src-d/go-git.v4/plumbing"
 	"sigs.k8s.io/yaml"
 
 	"github.com/stretchr/testify/assert"
@@ -82,6 +83,38 @@ const (
       username: deployer
     checkout:
       commit-hash: 01c4f7f32beb9851ae8f119a6b8e497d2b1e2bb8
+  remote-ref:
+    url: https://review.opendev.org/airship/treasuremap
+    checkout:
+      remote-ref: refs/changes/04/691202/5
+  mutually-exclusive-remote-ref:
+    url: https://review.opendev.org/airship/treasuremap
+    checkout:
+      remote-ref: refs/changes/04/691202/5
+      tag: v1.0.0
+  short-remote-ref:
+    url: https://review.opendev.org/airship/treasuremap
+    checkout:
+      remote-ref: changes/04/691202/5
+  sparse-clone:
+    url: https://opendev.org/airship/treasuremap
+    checkout:
+      branch: master
+    clone:
+      depth: 1
+      single-branch: true
+      sparse-paths:
+      - manifests/site/test-site
+      - manifests/function
+  negative-depth:
+    url: https://opendev.org/airship/treasuremap
+    clone:
+      depth: -1
+  escaping-sparse-path:
+    url: https://opendev.org/airship/treasuremap
+    clone:
+      sparse-paths:
+      - manifests/../../etc
   mutually-exclusive-auth-opts-ssh-pass:
     url: /home/ubuntu/some-gitrepo
     auth:
@@ -97,15 +130,20 @@ const (
 var (
 	TestCaseMap = map[string]*TestCase{
 		validateTestName: {
-			expectError:  false,
-			dataMapEntry: []string{"http-basic-auth", "ssh-key-auth", "no-auth", "empty-checkout"},
-			expectedNil:  false,
+			expectError: false,
+			dataMapEntry: []string{"http-basic-auth", "ssh-key-auth", "no-auth", "empty-checkout", "remote-ref",
+				"sparse-clone"},
+			expectedNil: false,
 		},
 		validateFailuresTestName: {
 			expectError: true,
 			dataMapEntry: []string{"wrong-type-auth",
 				"mutually-exclusive-auth-opts",
 				"mutually-exclusive-checkout-opts",
+				"mutually-exclusive-remote-ref",
+				"short-remote-ref",
+				"negative-depth",
+				"escaping-sparse-path",
 				"mutually-exclusive-auth-opts-ssh-key",
 				"mutually-exclusive-auth-opts-ssh-pass"},
 			expectedNil: false,
@@ -234,6 +272,24 @@ func TestToFetchOptions(t *testing.T) {
 	}
 }
 
+func TestRemoteRefOptions(t *testing.T) {
+	data := &TestRepos{}
+	err := yaml.Unmarshal([]byte(StringTestData), data)
+	require.NoError(t, err)
+
+	repo := data.TestData["remote-ref"]
+	require.NotNil(t, repo)
+	fo := repo.ToFetchOptions(nil)
+	require.Len(t, fo.RefSpecs, 1)
+	assert.Equal(t, "+refs/changes/04/691202/5:FETCH_HEAD", fo.RefSpecs[0].String())
+	assert.NoError(t, fo.Validate())
+	assert.Equal(t, config.FetchHead, repo.ToCheckoutOptions(false).Branch)
+
+	assert.Empty(t, data.TestData["no-auth"].ToFetchOptions(nil).RefSpecs)
+	assert.Equal(t, config.ErrInvalidRemoteRef{RemoteRef: "changes/04/691202/5"},
+		data.TestData["short-remote-ref"].Validate())
+}
+
 func TestToCloneOptions(t *testing.T) {
 	data := &TestRepos{}
 	err := yaml.Unmarshal([]byte(StringTestData), data)
@@ -248,6 +304,34 @@ func TestToCloneOptions(t *testing.T) {
 	}
 }
 
+func TestCloneOptions(t *testing.T) {
+	data := &TestRepos{}
+	err := yaml.Unmarshal([]byte(StringTestData), data)
+	require.NoError(t, err)
+
+	repo := data.TestData["sparse-clone"]
+	require.NotNil(t, repo)
+	co := repo.ToCloneOptions(nil)
+	assert.Equal(t, 1, co.Depth)
+	assert.True(t, co.SingleBranch)
+	assert.True(t, co.NoCheckout)
+	assert.Equal(t, plumbing.NewBranchReferenceName("master"), co.ReferenceName)
+	assert.NoError(t, co.Validate())
+	assert.Equal(t, 1, repo.ToFetchOptions(nil).Depth)
+	assert.Equal(t, []string{"manifests/site/test-site", "manifests/function"}, repo.ToSparsePaths())
+
+	noClone := data.TestData["no-auth"]
+	co = noClone.ToCloneOptions(nil)
+	assert.Zero(t, co.Depth)
+	assert.False(t, co.SingleBranch)
+	assert.False(t, co.NoCheckout)
+	assert.Empty(t, noClone.ToSparsePaths())
+
+	assert.Equal(t, config.ErrInvalidCloneDepth{Depth: -1}, data.TestData["negative-depth"].Validate())
+	assert.Equal(t, config.ErrInvalidSparsePath{Path: "manifests/../../etc"},
+		data.TestData["escaping-sparse-path"].Validate())
+}
+
 func TestURL(t *testing.T) {
 	data := &TestRepos{}
 	err := yaml.Unmarshal([]byte(StringTestData), data)
@@ -130,6 +130,11 @@ type Manifest struct {
 	// you would expect that at treasuremap/manifests you would have ephemeral/initinfra and
 	// ephemera/target directories, containing kustomize.yaml.
 	SubPath string `json:"sub-path"`
+	// LockPath is the path of the lock file pinning repositories to commits,
+	// relative paths are relative to TargetPath. The lock file is kept in
+	// TargetPath if it is not set, a path next to the config shares the lock
+	// along with the config
+	LockPath string `json:"lock-path,omitempty"`
 }
 
 // Repository is a tuple that holds the information for the remote sources of manifest yaml documents.
@@ -142,6 +147,8 @@ type Repository struct {
 	Auth *RepoAuth `json:"auth,omitempty"`
 	// CheckoutOptions holds options to checkout repository
 	CheckoutOptions *RepoCheckout `json:"checkout,omitempty"`
+	// CloneOptions limits history and files cloned from the repository
+	CloneOptions *RepoClone `json:"clone,omitempty"`
 }
 
 // RepoAuth struct describes method of authentication agaist given repository
@@ -161,6 +168,18 @@ type RepoAuth struct {
 	Username string `json:"username,omitempty"`
 }
 
+// RepoClone container holds options limiting what is cloned from repository
+type RepoClone struct {
+	// Depth limits fetched history to the given number of commits, whole history
+	// is fetched if it is not set
+	Depth int `json:"depth,omitempty"`
+	// SingleBranch fetches only the branch or tag set in checkout options
+	SingleBranch bool `json:"single-branch,omitempty"`
+	// SparsePaths limits the working tree to files under the given paths relative
+	// to the repository root, e.g. sub path of the manifest and function directories
+	SparsePaths []string `json:"sparse-paths,omitempty"`
+}
+
 // RepoCheckout container holds information how to checkout repository
 // Each field is mutually exclusive
 type RepoCheckout struct {
@@ -170,10 +189,8 @@ type RepoCheckout struct {
 	Branch string `json:"branch"`
 	// Tag is the tag name to checkout
 	Tag string `json:"tag"`
-	// RemoteRef is not supported currently TODO
 	// RemoteRef is used for remote checkouts such as gerrit change requests/github pull request
-	// for example refs/changes/04/691202/5
-	// TODO Add support for fetching remote refs
+	// for example refs/changes/04/691202/5, the ref is fetched to FETCH_HEAD and checked out
 	RemoteRef string `json:"remote-ref"`
 	// ForceCheckout is a boolean to indicate whether to use the `--force` option when checking out
 	ForceCheckout bool `json:"force"`
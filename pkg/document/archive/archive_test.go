@@ -0,0 +1,215 @@
+package archive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	fixtures "gopkg.in/src-d/go-git-fixtures.v3"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/archive"
+	"opendev.org/airship/airshipctl/pkg/document/lock"
+	"opendev.org/airship/airshipctl/pkg/document/repo"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/secret"
+	"opendev.org/airship/airshipctl/pkg/util"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestExportImport(t *testing.T) {
+	require.NoError(t, fixtures.Init())
+	defer testutil.CleanUpGitFixtures(t)
+	fx := fixtures.Basic().One()
+
+	srcDir, cleanupSrc := testutil.TempDir(t, "airshipctlExportTest-")
+	defer cleanupSrc(t)
+	dstDir, cleanupDst := testutil.TempDir(t, "airshipctlImportTest-")
+	defer cleanupDst(t)
+
+	conf := testutil.DummyConfig()
+	mfst := conf.Manifests["dummy_manifest"]
+	primary := &config.Repository{
+		URLString:       fx.DotGit().Root(),
+		CheckoutOptions: &config.RepoCheckout{Branch: "master"},
+	}
+	mfst.Repositories = map[string]*config.Repository{"primary": primary}
+	mfst.TargetPath = srcDir
+	repository, err := repo.NewRepository(srcDir, primary)
+	require.NoError(t, err)
+	require.NoError(t, repository.Download(true))
+	repository.Driver.Close()
+
+	ca, err := secret.NewCertificateAuthority(secret.CertificateConfig{CommonName: "archive"})
+	require.NoError(t, err)
+	keyPath := filepath.Join(srcDir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(keyPath, ca.KeyPEM(), 0600))
+	certPath := filepath.Join(srcDir, "cert.pem")
+	require.NoError(t, ioutil.WriteFile(certPath, ca.CertificatePEM(), 0600))
+
+	settings := &environment.AirshipCTLSettings{}
+	settings.SetConfig(conf)
+	archivePath := filepath.Join(srcDir, "manifests.tar.gz")
+	exportSettings := &archive.ExportSettings{AirshipCTLSettings: settings, Archive: archivePath, SignKey: keyPath}
+	out := &bytes.Buffer{}
+	require.NoError(t, exportSettings.Export(out))
+	assert.Equal(t, "primary: exported at "+fx.Head.String()+"\n"+
+		"Archive is signed, signature is written to "+archivePath+archive.SignatureExt+"\n", out.String())
+
+	mfst.TargetPath = dstDir
+	importSettings := &archive.ImportSettings{AirshipCTLSettings: settings, Archive: archivePath, VerifyKey: certPath}
+	out.Reset()
+	require.NoError(t, importSettings.Import(out))
+	assert.Equal(t, "primary: imported at "+fx.Head.String()+"\n", out.String())
+
+	dirName := util.GitDirNameFromURL(primary.URL())
+	expected, err := ioutil.ReadFile(filepath.Join(srcDir, dirName, "go", "example.go"))
+	require.NoError(t, err)
+	actual, err := ioutil.ReadFile(filepath.Join(dstDir, dirName, "go", "example.go"))
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+	_, err = os.Stat(filepath.Join(dstDir, dirName, ".git"))
+	assert.True(t, os.IsNotExist(err))
+
+	l, err := lock.Read(lock.Path(mfst))
+	require.NoError(t, err)
+	commit, found := l.Commit("primary", primary)
+	assert.True(t, found)
+	assert.Equal(t, fx.Head.String(), commit)
+
+	t.Run("Exists", func(t *testing.T) {
+		err := importSettings.Import(ioutil.Discard)
+		assert.IsType(t, archive.ErrRepositoryExists{}, err)
+
+		forced := *importSettings
+		forced.Force = true
+		assert.NoError(t, forced.Import(ioutil.Discard))
+	})
+
+	t.Run("InvalidSignature", func(t *testing.T) {
+		signature := filepath.Join(srcDir, "invalid.sig")
+		require.NoError(t, ioutil.WriteFile(signature, []byte("aW52YWxpZA==\n"), 0600))
+		invalid := *importSettings
+		invalid.Signature = signature
+		assert.IsType(t, archive.ErrInvalidSignature{}, invalid.Import(ioutil.Discard))
+	})
+
+	t.Run("NotInArchive", func(t *testing.T) {
+		mfst.Repositories["other"] = &config.Repository{
+			URLString:       "https://opendev.org/airship/other",
+			CheckoutOptions: &config.RepoCheckout{Branch: "master"},
+		}
+		defer delete(mfst.Repositories, "other")
+		err := importSettings.Import(ioutil.Discard)
+		assert.Equal(t, archive.ErrRepositoryNotInArchive{Name: "other"}, err)
+	})
+
+	t.Run("Modified", func(t *testing.T) {
+		mfst.TargetPath = srcDir
+		defer func() { mfst.TargetPath = dstDir }()
+		path := filepath.Join(srcDir, dirName, "CHANGELOG")
+		require.NoError(t, ioutil.WriteFile(path, []byte("modified"), 0600))
+		err := exportSettings.Export(ioutil.Discard)
+		assert.Equal(t, archive.ErrRepositoryModified{Name: "primary"}, err)
+	})
+}
+
+func TestImportInvalidArchive(t *testing.T) {
+	lockData, err := lock.New().Marshal()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		entries []*tar.Header
+	}{
+		{
+			name: "missing-lock",
+			entries: []*tar.Header{
+				{Name: "primary/README", Typeflag: tar.TypeReg, Mode: 0644},
+			},
+		},
+		{
+			name: "escaping-entry",
+			entries: []*tar.Header{
+				{Name: lock.FileName, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(lockData))},
+				{Name: "primary/../../README", Typeflag: tar.TypeReg, Mode: 0644},
+			},
+		},
+		{
+			name: "escaping-link",
+			entries: []*tar.Header{
+				{Name: lock.FileName, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(lockData))},
+				{Name: "primary/README", Typeflag: tar.TypeSymlink, Linkname: "../../README"},
+			},
+		},
+		{
+			// links are within the archive lexically but the second one is
+			// created through the first one and leads outside of it
+			name: "chained-links",
+			entries: []*tar.Header{
+				{Name: lock.FileName, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(lockData))},
+				{Name: "a/b/c/s1", Typeflag: tar.TypeSymlink, Linkname: "../.."},
+				{Name: "a/b/c/s1/s2", Typeflag: tar.TypeSymlink, Linkname: "../../.."},
+				{Name: "a/b/c/s1/s2/evil", Typeflag: tar.TypeReg, Mode: 0644},
+			},
+		},
+		{
+			// every link goes one directory up through the previous one
+			name: "links-through-links",
+			entries: []*tar.Header{
+				{Name: lock.FileName, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(lockData))},
+				{Name: "r/l1", Typeflag: tar.TypeSymlink, Linkname: "."},
+				{Name: "r/l2", Typeflag: tar.TypeSymlink, Linkname: "l1/.."},
+				{Name: "r/l3", Typeflag: tar.TypeSymlink, Linkname: "l2/.."},
+				{Name: "r/l4", Typeflag: tar.TypeSymlink, Linkname: "l3/.."},
+				{Name: "r/l5", Typeflag: tar.TypeSymlink, Linkname: "l4/.."},
+			},
+		},
+		{
+			name: "link-to-other-repository",
+			entries: []*tar.Header{
+				{Name: lock.FileName, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(lockData))},
+				{Name: "r/README", Typeflag: tar.TypeSymlink, Linkname: "../other/README"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, cleanup := testutil.TempDir(t, "airshipctlImportTest-")
+			defer cleanup(t)
+
+			archivePath := filepath.Join(tmpDir, "manifests.tar.gz")
+			f, err := os.Create(archivePath)
+			require.NoError(t, err)
+			gz := gzip.NewWriter(f)
+			tw := tar.NewWriter(gz)
+			for _, hdr := range tt.entries {
+				require.NoError(t, tw.WriteHeader(hdr))
+				if hdr.Name == lock.FileName {
+					_, err = tw.Write(lockData)
+					require.NoError(t, err)
+				}
+			}
+			require.NoError(t, tw.Close())
+			require.NoError(t, gz.Close())
+			require.NoError(t, f.Close())
+
+			conf := testutil.DummyConfig()
+			conf.Manifests["dummy_manifest"].TargetPath = filepath.Join(tmpDir, "target")
+			settings := &environment.AirshipCTLSettings{}
+			settings.SetConfig(conf)
+			importSettings := &archive.ImportSettings{AirshipCTLSettings: settings, Archive: archivePath}
+			assert.IsType(t, archive.ErrInvalidArchive{}, importSettings.Import(ioutil.Discard))
+			_, err = os.Lstat(filepath.Join(tmpDir, "evil"))
+			assert.True(t, os.IsNotExist(err))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package archive
+
+import (
+	"fmt"
+)
+
+// ErrRepositoryNotPulled returned if the repository to export is not cloned
+type ErrRepositoryNotPulled struct {
+	Name string
+	Err  error
+}
+
+func (e ErrRepositoryNotPulled) Error() string {
+	return fmt.Sprintf("Repository %s is not pulled: %v", e.Name, e.Err)
+}
+
+// ErrRepositoryModified returned if the working tree of the repository to
+// export has changes
+type ErrRepositoryModified struct {
+	Name string
+}
+
+func (e ErrRepositoryModified) Error() string {
+	return fmt.Sprintf("Working tree of repository %s has local changes, commit or discard them before export", e.Name)
+}
+
+// ErrInvalidKey returned if the key can't be parsed as RSA key
+type ErrInvalidKey struct {
+	Path string
+}
+
+func (e ErrInvalidKey) Error() string {
+	return fmt.Sprintf("File %s doesn't hold a PEM encoded RSA key or certificate", e.Path)
+}
+
+// ErrInvalidSignature returned if the archive doesn't match its signature
+type ErrInvalidSignature struct {
+	Archive string
+}
+
+func (e ErrInvalidSignature) Error() string {
+	return fmt.Sprintf("Signature of archive %s is not valid", e.Archive)
+}
+
+// ErrInvalidArchive returned if the archive is not an archive of manifest
+// repositories
+type ErrInvalidArchive struct {
+	Archive string
+	Reason  string
+}
+
+func (e ErrInvalidArchive) Error() string {
+	return fmt.Sprintf("Invalid archive %s: %s", e.Archive, e.Reason)
+}
+
+// ErrRepositoryNotInArchive returned if the repository of the manifest is not
+// in the archive or comes from another url
+type ErrRepositoryNotInArchive struct {
+	Name string
+}
+
+func (e ErrRepositoryNotInArchive) Error() string {
+	return fmt.Sprintf("Repository %s of the manifest is not in the archive", e.Name)
+}
+
+// ErrRepositoryExists returned if the working tree of the imported repository
+// exists in the target path and is not replaced
+type ErrRepositoryExists struct {
+	Path string
+}
+
+func (e ErrRepositoryExists) Error() string {
+	return fmt.Sprintf("Directory %s exists, use --force to replace it", e.Path)
+}
@@ -0,0 +1,214 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/filemode"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/lock"
+	"opendev.org/airship/airshipctl/pkg/document/repo"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// ExportSettings for exporting manifest repositories to an archive
+type ExportSettings struct {
+	*environment.AirshipCTLSettings
+	// Archive is the path of the gzipped tar archive to write
+	Archive string
+	// SignKey is the path of the PEM encoded RSA private key signing the
+	// archive, the archive is not signed if it is not set
+	SignKey string
+}
+
+// exportedRepository is the repository open for export
+type exportedRepository struct {
+	name       string
+	repository *repo.Repository
+	commit     *object.Commit
+}
+
+// Export writes working trees of manifest repositories along with the lock
+// file recording commits they are checked out at to the archive. Files are
+// taken from checked out commits, so working trees must have no changes.
+// Only files under sparse paths are exported for sparse checkouts
+func (s *ExportSettings) Export(out io.Writer) error {
+	manifest, err := s.Config().CurrentContextManifest()
+	if err != nil {
+		return err
+	}
+	if _, exists := manifest.Repositories[manifest.PrimaryRepositoryName]; !exists {
+		return config.ErrMissingPrimaryRepo{}
+	}
+
+	names := repositoryNames(manifest)
+	l := lock.New()
+	repos := make([]*exportedRepository, 0, len(names))
+	defer func() {
+		for _, r := range repos {
+			r.repository.Driver.Close()
+		}
+	}()
+	for _, name := range names {
+		var r *exportedRepository
+		if r, err = openForExport(manifest.TargetPath, name, manifest.Repositories[name]); err != nil {
+			return err
+		}
+		repos = append(repos, r)
+		l.Set(name, manifest.Repositories[name], r.commit.Hash.String())
+	}
+
+	if err = writeArchive(s.Archive, l, repos); err != nil {
+		return err
+	}
+	for _, r := range repos {
+		fmt.Fprintf(out, "%s: exported at %s\n", r.name, r.commit.Hash)
+	}
+	if s.SignKey != "" {
+		if err = signFile(s.Archive, s.SignKey); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Archive is signed, signature is written to %s\n", s.Archive+SignatureExt)
+	}
+	return nil
+}
+
+// repositoryNames returns sorted names of manifest repositories
+func repositoryNames(manifest *config.Manifest) []string {
+	names := make([]string, 0, len(manifest.Repositories))
+	for name := range manifest.Repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// openForExport opens the repository and returns it along with the commit
+// it is checked out at
+func openForExport(basePath, name string, repoConfig *config.Repository) (_ *exportedRepository, err error) {
+	repository, err := repo.NewRepository(basePath, repoConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err = repository.Open(); err != nil {
+		return nil, ErrRepositoryNotPulled{Name: name, Err: err}
+	}
+	defer func() {
+		if err != nil {
+			repository.Driver.Close()
+		}
+	}()
+
+	clean, err := repository.IsClean()
+	if err != nil {
+		return nil, err
+	}
+	if !clean {
+		return nil, ErrRepositoryModified{Name: name}
+	}
+	head, err := repository.Driver.Head()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repository.Driver.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return &exportedRepository{name: name, repository: repository, commit: commit}, nil
+}
+
+// writeArchive writes the archive atomically, the lock file goes first so
+// that it is read before working trees on import
+func writeArchive(archivePath string, l *lock.Lock, repos []*exportedRepository) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(archivePath), ".archive")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	gz := gzip.NewWriter(tmp)
+	tw := tar.NewWriter(gz)
+	err = writeEntries(tw, l, repos)
+	for _, closer := range []io.Closer{tw, gz, tmp} {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), archivePath)
+}
+
+func writeEntries(tw *tar.Writer, l *lock.Lock, repos []*exportedRepository) error {
+	data, err := l.Marshal()
+	if err != nil {
+		return err
+	}
+	// entries get commit times so that archives of the same commits are equal
+	var modTime time.Time
+	for _, r := range repos {
+		if r.commit.Committer.When.After(modTime) {
+			modTime = r.commit.Committer.When
+		}
+	}
+	hdr := &tar.Header{Name: lock.FileName, Mode: 0600, Size: int64(len(data)), ModTime: modTime}
+	if err = tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err = tw.Write(data); err != nil {
+		return err
+	}
+
+	for _, r := range repos {
+		tree, err := r.commit.Tree()
+		if err != nil {
+			return err
+		}
+		sparsePaths := r.repository.ToSparsePaths()
+		err = tree.Files().ForEach(func(f *object.File) error {
+			if !repo.InSparsePaths(f.Name, sparsePaths) {
+				return nil
+			}
+			return writeFile(tw, path.Join(r.repository.Name, f.Name), f, r.commit.Committer.When)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(tw *tar.Writer, name string, f *object.File, modTime time.Time) error {
+	contents, err := f.Contents()
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{Name: name, Mode: 0644, ModTime: modTime}
+	switch f.Mode {
+	case filemode.Symlink:
+		hdr.Typeflag = tar.TypeSymlink
+		hdr.Linkname = contents
+		hdr.Mode = 0777
+		return tw.WriteHeader(hdr)
+	case filemode.Executable:
+		hdr.Mode = 0755
+	}
+	hdr.Typeflag = tar.TypeReg
+	hdr.Size = int64(len(contents))
+	if err = tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.WriteString(tw, contents)
+	return err
+}
@@ -0,0 +1,298 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/lock"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// ImportSettings for importing manifest repositories from an archive
+type ImportSettings struct {
+	*environment.AirshipCTLSettings
+	// Archive is the path of the archive written by export
+	Archive string
+	// VerifyKey is the path of the PEM encoded RSA public key, certificate or
+	// private key verifying the signature of the archive, the signature is
+	// not checked if it is not set
+	VerifyKey string
+	// Signature is the path of the signature, Archive with SignatureExt is
+	// used if it is not set
+	Signature string
+	// Force replaces working trees existing in the target path
+	Force bool
+}
+
+// Import unpacks working trees of manifest repositories from the archive to
+// the target path of the manifest and writes the lock file of the archive
+// there. Imported working trees are not git repositories, they are meant
+// for building bundles where git remotes are not available
+func (s *ImportSettings) Import(out io.Writer) error {
+	manifest, err := s.Config().CurrentContextManifest()
+	if err != nil {
+		return err
+	}
+	if err = s.verify(); err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(manifest.TargetPath, 0750); err != nil {
+		return err
+	}
+	tmpDir, err := ioutil.TempDir(manifest.TargetPath, ".import")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	archived, err := s.extract(tmpDir)
+	if err != nil {
+		return err
+	}
+	imported, err := s.importedLock(manifest, archived)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range repositoryNames(manifest) {
+		dirName := util.GitDirNameFromURL(manifest.Repositories[name].URL())
+		if err = replaceDir(filepath.Join(tmpDir, dirName), filepath.Join(manifest.TargetPath, dirName)); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s: imported at %s\n", name, imported.Repositories[name].Commit)
+	}
+	return imported.Write(lock.Path(manifest))
+}
+
+// verify checks the signature of the archive if the verification key is set
+func (s *ImportSettings) verify() error {
+	if s.VerifyKey == "" {
+		return nil
+	}
+	signature := s.Signature
+	if signature == "" {
+		signature = s.Archive + SignatureExt
+	}
+	return verifyFile(s.Archive, signature, s.VerifyKey)
+}
+
+// importedLock returns the lock of manifest repositories found in the lock of
+// the archive. Every repository is checked before any working tree is replaced
+func (s *ImportSettings) importedLock(manifest *config.Manifest, archived *lock.Lock) (*lock.Lock, error) {
+	imported := lock.New()
+	for _, name := range repositoryNames(manifest) {
+		repoConfig := manifest.Repositories[name]
+		commit, found := archived.Commit(name, repoConfig)
+		if !found {
+			return nil, ErrRepositoryNotInArchive{Name: name}
+		}
+		imported.Set(name, repoConfig, commit)
+		dst := filepath.Join(manifest.TargetPath, util.GitDirNameFromURL(repoConfig.URL()))
+		if _, err := os.Lstat(dst); err == nil && !s.Force {
+			return nil, ErrRepositoryExists{Path: dst}
+		}
+	}
+	return imported, nil
+}
+
+// replaceDir moves the extracted working tree to its destination
+func replaceDir(src, dst string) error {
+	// repositories with no exported files have no entries in the archive
+	if err := os.MkdirAll(src, 0750); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	return os.Rename(src, dst)
+}
+
+// extract unpacks the archive to the directory and returns its lock
+func (s *ImportSettings) extract(dir string) (*lock.Lock, error) {
+	f, err := os.Open(s.Archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, ErrInvalidArchive{Archive: s.Archive, Reason: err.Error()}
+	}
+	defer gz.Close()
+	// entries are checked against the directory with links resolved
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return nil, err
+	}
+
+	var l *lock.Lock
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidArchive{Archive: s.Archive, Reason: err.Error()}
+		}
+		name, ok := entryPath(hdr.Name)
+		if !ok {
+			reason := fmt.Sprintf("entry %q is outside of the archive", hdr.Name)
+			return nil, ErrInvalidArchive{Archive: s.Archive, Reason: reason}
+		}
+		if name == lock.FileName {
+			l, err = readLock(tr, s.Archive+":"+name)
+		} else {
+			err = s.extractEntry(dir, name, hdr, tr)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if l == nil {
+		return nil, ErrInvalidArchive{Archive: s.Archive, Reason: "lock file " + lock.FileName + " is missing"}
+	}
+	return l, nil
+}
+
+func readLock(r io.Reader, source string) (*lock.Lock, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return lock.Parse(data, source)
+}
+
+func (s *ImportSettings) extractEntry(dir, name string, hdr *tar.Header, r io.Reader) error {
+	target := filepath.Join(dir, filepath.FromSlash(name))
+	// entries stay within their repository since working trees are moved
+	// to the target path one by one
+	root := filepath.Join(dir, strings.SplitN(name, "/", 2)[0])
+	// links extracted before may lead anywhere, so entries are checked with
+	// links on their paths resolved before anything is written
+	resolved, err := resolvePath(target)
+	if err != nil {
+		return err
+	}
+	if !withinDir(root, resolved) {
+		return ErrInvalidArchive{Archive: s.Archive, Reason: fmt.Sprintf("entry %q leads outside of the archive", hdr.Name)}
+	}
+	if err = os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+		return err
+	}
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, 0750)
+	case tar.TypeReg, tar.TypeRegA:
+		perm := os.FileMode(0644)
+		if hdr.Mode&0111 != 0 {
+			perm = 0755
+		}
+		f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(f, r); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	case tar.TypeSymlink:
+		// links may not point outside of their repository
+		dst, ok := linkDestination(filepath.Dir(resolved), hdr.Linkname)
+		if !ok || !withinDir(root, dst) {
+			return ErrInvalidArchive{Archive: s.Archive, Reason: fmt.Sprintf("link %q points outside of the archive", hdr.Name)}
+		}
+		return os.Symlink(hdr.Linkname, target)
+	default:
+		return ErrInvalidArchive{Archive: s.Archive, Reason: fmt.Sprintf("entry %q is not a file or directory", hdr.Name)}
+	}
+}
+
+// resolvePath returns the path with links of its longest existing prefix
+// resolved, the rest of the path is kept as is
+func resolvePath(p string) (string, error) {
+	existing, rest := p, ""
+	for {
+		_, err := os.Lstat(existing)
+		if err == nil {
+			break
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(resolved, rest), nil
+}
+
+// linkDestination resolves the link name from the resolved directory of the
+// link element by element, following links extracted before the way they are
+// followed once the link is used. Absolute names are not resolved, neither
+// are ".." elements following missing paths since those may be extracted as
+// links later
+func linkDestination(linkDir, linkname string) (string, bool) {
+	if path.IsAbs(linkname) {
+		return "", false
+	}
+	dst, missing := linkDir, false
+	for _, elem := range strings.Split(linkname, "/") {
+		switch {
+		case elem == "" || elem == ".":
+		case elem == "..":
+			if missing {
+				return "", false
+			}
+			dst = filepath.Dir(dst)
+		case missing:
+			dst = filepath.Join(dst, elem)
+		default:
+			next := filepath.Join(dst, elem)
+			resolved, err := filepath.EvalSymlinks(next)
+			switch {
+			case err == nil:
+				dst = resolved
+			case os.IsNotExist(err):
+				// dangling links are not followed either, paths under them
+				// stay within destinations of the links
+				dst, missing = next, true
+			default:
+				return "", false
+			}
+		}
+	}
+	return dst, true
+}
+
+// withinDir tells if the path is the directory or a path under it
+func withinDir(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// entryPath returns the clean path of the entry, entries must be within the archive
+func entryPath(name string) (string, bool) {
+	cleaned := path.Clean(name)
+	if path.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", false
+	}
+	return cleaned, true
+}
@@ -0,0 +1,115 @@
+package archive
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+// SignatureExt is appended to the archive path to get the path of its signature
+const SignatureExt = ".sig"
+
+// signFile writes the base64 encoded RSA signature of the SHA-256 digest of
+// the file next to it
+func signFile(path, keyPath string) error {
+	keyPEM, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return err
+	}
+	key, err := secret.ParsePrivateKey(keyPEM)
+	if err != nil {
+		return ErrInvalidKey{Path: keyPath}
+	}
+	digest, err := fileDigest(path)
+	if err != nil {
+		return err
+	}
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path+SignatureExt, []byte(base64.StdEncoding.EncodeToString(signature)+"\n"), 0600)
+}
+
+// verifyFile checks the signature of the file with the public key
+func verifyFile(path, signaturePath, keyPath string) error {
+	key, err := readPublicKey(keyPath)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(signaturePath)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return ErrInvalidSignature{Archive: path}
+	}
+	digest, err := fileDigest(path)
+	if err != nil {
+		return err
+	}
+	if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, signature) != nil {
+		return ErrInvalidSignature{Archive: path}
+	}
+	return nil
+}
+
+// readPublicKey reads the RSA public key from the PEM encoded public key,
+// certificate or private key
+func readPublicKey(keyPath string) (*rsa.PublicKey, error) {
+	keyPEM, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, ErrInvalidKey{Path: keyPath}
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	case "RSA PRIVATE KEY":
+		var private *rsa.PrivateKey
+		if private, err = secret.ParsePrivateKey(keyPEM); err == nil {
+			key = &private.PublicKey
+		}
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if err != nil || !ok {
+		return nil, ErrInvalidKey{Path: keyPath}
+	}
+	return rsaKey, nil
+}
+
+func fileDigest(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
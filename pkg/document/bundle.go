@@ -18,7 +18,6 @@ import (
 
 	docplugins "opendev.org/airship/airshipctl/pkg/document/plugins"
 	"opendev.org/airship/airshipctl/pkg/log"
-	utilyaml "opendev.org/airship/airshipctl/pkg/util/yaml"
 )
 
 func init() {
@@ -39,11 +38,15 @@ type BundleFactory struct {
 	KustomizeBuildOptions
 	resmap.ResMap
 	FileSystem
+
+	// origins of resources loaded from files
+	origins map[*resource.Resource]*Origin
 }
 
 // Bundle interface provides the specification for a bundle implementation
 type Bundle interface {
 	Write(out io.Writer) error
+	Encode(out io.Writer, encoder Encoder) error
 	SetFileSystem(FileSystem) error
 	GetFileSystem() FileSystem
 	Select(selector Selector) ([]Document, error)
@@ -55,12 +58,25 @@ type Bundle interface {
 	GetByAnnotation(annotationSelector string) ([]Document, error)
 	GetByLabel(labelSelector string) ([]Document, error)
 	GetAllDocuments() ([]Document, error)
+	Append(Document) error
+	Replace(Document) error
+	Delete(Selector) error
+	Patch(Selector, []byte) error
 }
 
 // NewBundleByPath helper function that returns new document.Bundle interface based on clusterType and
-// phase, example: helpers.NewBunde(airConfig, "ephemeral", "initinfra")
+// phase, example: helpers.NewBunde(airConfig, "ephemeral", "initinfra").
+// Bundles are taken from the bundle cache unless it is disabled
 func NewBundleByPath(rootPath string) (Bundle, error) {
-	return NewBundle(NewDocumentFs(), rootPath, "")
+	if !BundleCacheEnabled() {
+		return NewBundle(NewDocumentFs(), rootPath, "")
+	}
+	cache, err := NewBundleCache()
+	if err != nil {
+		log.Debugf("Bundle cache is not available: %v", err)
+		return NewBundle(NewDocumentFs(), rootPath, "")
+	}
+	return cache.Bundle(NewDocumentFs(), rootPath)
 }
 
 // NewBundle is a convenience function to create a new bundle
@@ -85,9 +101,9 @@ func NewBundle(fSys FileSystem, kustomizePath string, outputPath string) (Bundle
 	}
 
 	// boiler plate to allow us to run Kustomize build
-	uf := kunstruct.NewKunstructuredFactoryImpl()
+	tracker := newOriginTracker(kunstruct.NewKunstructuredFactoryImpl())
 	pf := transformer.NewFactoryImpl()
-	rf := resmap.NewFactory(resource.NewFactory(uf), pf)
+	rf := resmap.NewFactory(resource.NewFactory(tracker), pf)
 	v := validator.NewKustValidator()
 
 	pluginConfig := plugins.DefaultPluginConfig()
@@ -105,7 +121,7 @@ func NewBundle(fSys FileSystem, kustomizePath string, outputPath string) (Bundle
 		}
 	}()
 
-	kt, err := target.NewKustTarget(ldr, rf, pf, pl)
+	kt, err := target.NewKustTarget(tracker.loader(ldr, nil), rf, pf, pl)
 	if err != nil {
 		return bundle, err
 	}
@@ -115,6 +131,7 @@ func NewBundle(fSys FileSystem, kustomizePath string, outputPath string) (Bundle
 	if err != nil {
 		return bundle, err
 	}
+	bundle.origins = tracker.resolve(m)
 	err = bundle.SetKustomizeResourceMap(m)
 	if err != nil {
 		return nil, err
@@ -123,6 +140,12 @@ func NewBundle(fSys FileSystem, kustomizePath string, outputPath string) (Bundle
 	return bundle, err
 }
 
+// newResMapFactory returns the factory of resource maps used by builds
+func newResMapFactory() *resmap.Factory {
+	uf := kunstruct.NewKunstructuredFactoryImpl()
+	return resmap.NewFactory(resource.NewFactory(uf), transformer.NewFactoryImpl())
+}
+
 // GetKustomizeResourceMap returns a Kustomize Resource Map for this bundle
 func (b *BundleFactory) GetKustomizeResourceMap() resmap.ResMap {
 	return b.ResMap
@@ -164,12 +187,19 @@ func (b *BundleFactory) OrderLegacy() error {
 	return builtin.NewLegacyOrderTransformerPlugin().Transform(b.GetKustomizeResourceMap())
 }
 
+// newDocument returns the document of the resource along with its origin
+func (b *BundleFactory) newDocument(res *resource.Resource) (Document, error) {
+	doc := &Factory{origin: b.origins[res]}
+	err := doc.SetKustomizeResource(res)
+	return doc, err
+}
+
 // GetAllDocuments returns all documents in this bundle
 func (b *BundleFactory) GetAllDocuments() ([]Document, error) {
 	docSet := make([]Document, len(b.ResMap.Resources()))
 	for i, res := range b.ResMap.Resources() {
 		// Construct Bundle document for each resource returned
-		doc, err := NewDocument(res)
+		doc, err := b.newDocument(res)
 		if err != nil {
 			return docSet, err
 		}
@@ -195,7 +225,7 @@ func (b *BundleFactory) GetByName(name string) (Document, error) {
 	case found > 1:
 		return &Factory{}, fmt.Errorf("more than one document found with name %s", name)
 	default:
-		return NewDocument(resSet[0])
+		return b.newDocument(resSet[0])
 	}
 }
 
@@ -212,7 +242,7 @@ func (b *BundleFactory) Select(selector Selector) ([]Document, error) {
 	docSet := make([]Document, len(resources))
 	for i, res := range resources {
 		var doc Document
-		doc, err = NewDocument(res)
+		doc, err = b.newDocument(res)
 		if err != nil {
 			return docSet, err
 		}
@@ -272,6 +302,7 @@ func (b *BundleFactory) SelectBundle(selector Selector) (Bundle, error) {
 		KustomizeBuildOptions: b.KustomizeBuildOptions,
 		ResMap:                resourceMap,
 		FileSystem:            b.FileSystem,
+		origins:               b.origins,
 	}, nil
 }
 
@@ -332,6 +363,7 @@ func (b *BundleFactory) SelectByFieldValue(path string, condition func(interface
 	result := &BundleFactory{
 		KustomizeBuildOptions: b.KustomizeBuildOptions,
 		FileSystem:            b.FileSystem,
+		origins:               b.origins,
 	}
 	resourceMap := resmap.New()
 	for _, res := range b.Resources() {
@@ -384,11 +416,14 @@ func (b *BundleFactory) GetByGvk(group, version, kind string) ([]Document, error
 
 // Write will write out the entire bundle resource map
 func (b *BundleFactory) Write(out io.Writer) error {
-	for _, res := range b.ResMap.Resources() {
-		err := utilyaml.WriteOut(out, res)
-		if err != nil {
-			return err
-		}
+	return b.Encode(out, EncoderFunc(encodeYAML))
+}
+
+// Encode writes out all documents of the bundle using the encoder
+func (b *BundleFactory) Encode(out io.Writer, encoder Encoder) error {
+	docs, err := b.GetAllDocuments()
+	if err != nil {
+		return err
 	}
-	return nil
+	return encoder.Encode(out, docs)
 }
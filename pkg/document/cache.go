@@ -0,0 +1,378 @@
+package document
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	docplugins "opendev.org/airship/airshipctl/pkg/document/plugins"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+const (
+	// BundleCacheDirEnv is the environment variable holding the directory of
+	// the bundle cache, BundleCacheDir in the airship config directory is
+	// used if it is not set
+	BundleCacheDirEnv = "AIRSHIP_BUNDLE_CACHE_DIR"
+	// NoBundleCacheEnv disables the bundle cache if it is set to any value
+	NoBundleCacheEnv = "AIRSHIP_NO_BUNDLE_CACHE"
+	// BundleCacheDir is the name of the bundle cache directory in the
+	// airship config directory
+	BundleCacheDir = "cache/bundles"
+	// DefaultBundleCacheEntries is the number of entries the bundle cache
+	// keeps if the limit is not set
+	DefaultBundleCacheEntries = 64
+
+	// bundleCacheVersion changes whenever the format of cache entries or the
+	// way bundles are built changes
+	bundleCacheVersion = 2
+
+	absentInput = "absent"
+	dirInput    = "dir"
+)
+
+// BundleCache keeps built bundles on disk. Every entry holds the resources
+// of the bundle built from the kustomization root along with content hashes
+// of every file the loader touched and the plugin configuration of the build,
+// the entry is only used if all of them match. Bundles built from files with
+// encrypted values are never cached so that decrypted secrets don't reach
+// the disk
+type BundleCache struct {
+	// Dir is the directory holding cache entries
+	Dir string
+	// MaxEntries is the number of entries kept in the cache, least recently
+	// written entries are removed once there are more of them.
+	// DefaultBundleCacheEntries is used if it is not set
+	MaxEntries int
+}
+
+// bundleCacheEntry is the bundle built from the kustomization root
+type bundleCacheEntry struct {
+	Version int    `json:"version"`
+	Root    string `json:"root"`
+	Plugins string `json:"plugins"`
+	// Inputs are content hashes of paths the loader touched
+	Inputs    map[string]string `json:"inputs"`
+	Resources string            `json:"resources"`
+	// Origins are origins of resources in the same order
+	Origins []*Origin `json:"origins"`
+}
+
+// NewBundleCache returns the cache in the directory set by BundleCacheDirEnv
+// or in the airship config directory
+func NewBundleCache() (*BundleCache, error) {
+	dir := os.Getenv(BundleCacheDirEnv)
+	if dir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(homeDir, config.AirshipConfigDir, BundleCacheDir)
+	}
+	return &BundleCache{Dir: dir}, nil
+}
+
+// BundleCacheEnabled tells if NewBundleByPath uses the bundle cache
+func BundleCacheEnabled() bool {
+	return os.Getenv(NoBundleCacheEnv) == ""
+}
+
+// Bundle returns the bundle of the kustomization root from the cache, the
+// bundle is built and cached if the entry is missing or out of date.
+// Failures to read or write the cache are not errors
+func (c *BundleCache) Bundle(fSys FileSystem, kustomizePath string) (Bundle, error) {
+	root, err := filepath.Abs(kustomizePath)
+	if err != nil {
+		return nil, err
+	}
+	pluginKey := bundlePluginKey()
+	entryPath := filepath.Join(c.Dir, cacheKey(root, pluginKey)+".json")
+
+	bundle, err := c.load(fSys, entryPath, root, kustomizePath)
+	switch {
+	case err != nil:
+		log.Debugf("Bundle cache entry %s is not used: %v", entryPath, err)
+	case bundle != nil:
+		log.Debugf("Bundle %s is loaded from cache", root)
+		return bundle, nil
+	}
+
+	rfs := &recordingFs{FileSystem: fSys, inputs: make(map[string]string)}
+	bundle, err = NewBundle(rfs, kustomizePath, "")
+	if err != nil {
+		return bundle, err
+	}
+	if err = bundle.SetFileSystem(fSys); err != nil {
+		return nil, err
+	}
+
+	if rfs.encrypted {
+		log.Debugf("Bundle %s is not cached since it has encrypted values", root)
+		return bundle, nil
+	}
+	buf := &bytes.Buffer{}
+	if err = bundle.Write(buf); err != nil {
+		return nil, err
+	}
+	entry := &bundleCacheEntry{
+		Version:   bundleCacheVersion,
+		Root:      root,
+		Plugins:   pluginKey,
+		Inputs:    rfs.inputs,
+		Resources: buf.String(),
+	}
+	if bf, ok := bundle.(*BundleFactory); ok {
+		for _, res := range bf.Resources() {
+			entry.Origins = append(entry.Origins, bf.origins[res])
+		}
+	}
+	if err = c.store(entryPath, entry); err != nil {
+		log.Debugf("Failed to cache bundle %s: %v", root, err)
+	} else if err = c.trim(); err != nil {
+		log.Debugf("Failed to remove old entries of bundle cache %s: %v", c.Dir, err)
+	}
+	return bundle, nil
+}
+
+// Clean removes all cache entries and returns their number
+func (c *BundleCache) Clean() (int, error) {
+	entries, err := filepath.Glob(filepath.Join(c.Dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		if err = os.Remove(entry); err != nil {
+			return 0, err
+		}
+	}
+	return len(entries), nil
+}
+
+// trim removes least recently written entries while the cache has more
+// entries than allowed
+func (c *BundleCache) trim() error {
+	maxEntries := c.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultBundleCacheEntries
+	}
+	entries, err := filepath.Glob(filepath.Join(c.Dir, "*.json"))
+	if err != nil || len(entries) <= maxEntries {
+		return err
+	}
+
+	// entries removed by concurrent builds have zero time and go first
+	modTimes := make(map[string]time.Time, len(entries))
+	for _, entry := range entries {
+		if info, statErr := os.Stat(entry); statErr == nil {
+			modTimes[entry] = info.ModTime()
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return modTimes[entries[i]].Before(modTimes[entries[j]])
+	})
+	for _, entry := range entries[:len(entries)-maxEntries] {
+		if err = os.Remove(entry); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// load returns the cached bundle or nil if the entry doesn't exist
+func (c *BundleCache) load(fSys FileSystem, entryPath, root, kustomizePath string) (Bundle, error) {
+	data, err := ioutil.ReadFile(entryPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entry := &bundleCacheEntry{}
+	if err = json.Unmarshal(data, entry); err != nil {
+		return nil, err
+	}
+
+	if entry.Version != bundleCacheVersion || entry.Root != root || entry.Plugins != bundlePluginKey() {
+		return nil, ErrBundleCacheOutdated{Reason: "build settings have changed"}
+	}
+	// inputs are checked in the same order every time to report the same
+	// outdated input
+	paths := make([]string, 0, len(entry.Inputs))
+	for path := range entry.Inputs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if inputState(fSys, path) != entry.Inputs[path] {
+			return nil, ErrBundleCacheOutdated{Reason: path + " has changed"}
+		}
+	}
+
+	m, err := newResMapFactory().NewResMapFromBytes([]byte(entry.Resources))
+	if err != nil {
+		return nil, err
+	}
+	bundle := &BundleFactory{
+		KustomizeBuildOptions: KustomizeBuildOptions{
+			KustomizationPath: kustomizePath,
+			LoadRestrictor:    loader.RestrictionRootOnly,
+		},
+		ResMap:     m,
+		FileSystem: fSys,
+		origins:    make(map[*resource.Resource]*Origin),
+	}
+	for i, res := range m.Resources() {
+		if i < len(entry.Origins) && entry.Origins[i] != nil {
+			bundle.origins[res] = entry.Origins[i]
+		}
+	}
+	return bundle, nil
+}
+
+// store writes the entry atomically so that concurrent builds never see
+// partial entries
+func (c *BundleCache) store(entryPath string, entry *bundleCacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(c.Dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(c.Dir, ".entry")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), entryPath)
+}
+
+// recordingFs records states of paths the loader touches
+type recordingFs struct {
+	FileSystem
+	inputs map[string]string
+	// encrypted is set if any of the files read holds encrypted values
+	encrypted bool
+}
+
+func (r *recordingFs) record(path string) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if _, found := r.inputs[path]; found {
+		return
+	}
+	if r.FileSystem.IsDir(path) {
+		r.inputs[path] = dirInput
+		return
+	}
+	data, err := r.FileSystem.ReadFile(path)
+	if err != nil {
+		r.inputs[path] = absentInput
+		return
+	}
+	r.inputs[path] = contentHash(data)
+	r.encrypted = r.encrypted || secret.ContainsEncryptedValues(data)
+}
+
+// ReadFile records the file and reads it
+func (r *recordingFs) ReadFile(path string) ([]byte, error) {
+	r.record(path)
+	return r.FileSystem.ReadFile(path)
+}
+
+// Open records the file and opens it
+func (r *recordingFs) Open(path string) (fs.File, error) {
+	r.record(path)
+	return r.FileSystem.Open(path)
+}
+
+// Exists records the path and tells if it exists
+func (r *recordingFs) Exists(path string) bool {
+	r.record(path)
+	return r.FileSystem.Exists(path)
+}
+
+// IsDir records the path and tells if it is a directory
+func (r *recordingFs) IsDir(path string) bool {
+	r.record(path)
+	return r.FileSystem.IsDir(path)
+}
+
+// Glob records the pattern along with its matches
+func (r *recordingFs) Glob(pattern string) ([]string, error) {
+	matches, err := r.FileSystem.Glob(pattern)
+	if err == nil {
+		r.inputs["glob:"+pattern] = globState(matches)
+	}
+	return matches, err
+}
+
+// inputState returns the content hash of the file or tells if the path is a
+// directory or doesn't exist
+func inputState(fSys FileSystem, path string) string {
+	if strings.HasPrefix(path, "glob:") {
+		matches, err := fSys.Glob(strings.TrimPrefix(path, "glob:"))
+		if err != nil {
+			return absentInput
+		}
+		return globState(matches)
+	}
+	if fSys.IsDir(path) {
+		return dirInput
+	}
+	data, err := fSys.ReadFile(path)
+	if err != nil {
+		return absentInput
+	}
+	return contentHash(data)
+}
+
+func globState(matches []string) string {
+	sorted := append([]string{}, matches...)
+	sort.Strings(sorted)
+	return contentHash([]byte(strings.Join(sorted, "\n")))
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// bundlePluginKey identifies the plugin configuration of builds along with
+// the executable building them, since plugins are compiled in
+func bundlePluginKey() string {
+	key := fmt.Sprintf("%s;%+v", docplugins.Fingerprint(), plugins.DefaultPluginConfig())
+	if exe, err := os.Executable(); err == nil {
+		if info, err := os.Stat(exe); err == nil {
+			key = fmt.Sprintf("%s;%s;%d;%d", key, exe, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return key
+}
+
+func cacheKey(parts ...string) string {
+	return contentHash([]byte(strings.Join(parts, "\x00")))
+}
@@ -0,0 +1,149 @@
+package document_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const configMapTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cached
+data:
+  value: %s
+`
+
+func TestBundleCache(t *testing.T) {
+	kustomizePath, cache, cleanup := makeCachedManifest(t, "v1")
+	defer cleanup(t)
+
+	bundle, err := cache.Bundle(document.NewDocumentFs(), kustomizePath)
+	require.NoError(t, err)
+	assertCachedValue(t, bundle, "v1")
+	entries := cacheEntries(t, cache)
+	require.Len(t, entries, 1)
+
+	// the entry is used as is while inputs stay the same
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(entries[0], old, old))
+	bundle, err = cache.Bundle(document.NewDocumentFs(), kustomizePath)
+	require.NoError(t, err)
+	assertCachedValue(t, bundle, "v1")
+	info, err := os.Stat(entries[0])
+	require.NoError(t, err)
+	assert.True(t, old.Equal(info.ModTime()))
+	doc, err := bundle.GetByName("cached")
+	require.NoError(t, err)
+	require.NotNil(t, doc.Origin())
+	assert.Equal(t, filepath.Join(kustomizePath, "configmap.yaml"), doc.Origin().Path)
+
+	// changed inputs invalidate the entry
+	writeManifestFile(t, kustomizePath, "configmap.yaml", fmt.Sprintf(configMapTemplate, "v2"))
+	bundle, err = cache.Bundle(document.NewDocumentFs(), kustomizePath)
+	require.NoError(t, err)
+	assertCachedValue(t, bundle, "v2")
+	assert.Len(t, cacheEntries(t, cache), 1)
+
+	removed, err := cache.Clean()
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Empty(t, cacheEntries(t, cache))
+}
+
+func TestBundleCacheEncryptedInputs(t *testing.T) {
+	kustomizePath, cache, cleanup := makeCachedManifest(t, "ENC[AES256_GCM,c2VjcmV0]")
+	defer cleanup(t)
+
+	bundle, err := cache.Bundle(document.NewDocumentFs(), kustomizePath)
+	require.NoError(t, err)
+	assertCachedValue(t, bundle, "ENC[AES256_GCM,c2VjcmV0]")
+	assert.Empty(t, cacheEntries(t, cache))
+}
+
+func TestBundleCacheMaxEntries(t *testing.T) {
+	firstPath, cache, cleanupFirst := makeCachedManifest(t, "v1")
+	defer cleanupFirst(t)
+	secondPath, _, cleanupSecond := makeCachedManifest(t, "v2")
+	defer cleanupSecond(t)
+	cache.MaxEntries = 1
+
+	_, err := cache.Bundle(document.NewDocumentFs(), firstPath)
+	require.NoError(t, err)
+	entries := cacheEntries(t, cache)
+	require.Len(t, entries, 1)
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(entries[0], old, old))
+
+	// the oldest entry is removed once the cache is full
+	_, err = cache.Bundle(document.NewDocumentFs(), secondPath)
+	require.NoError(t, err)
+	remaining := cacheEntries(t, cache)
+	require.Len(t, remaining, 1)
+	assert.NotEqual(t, entries[0], remaining[0])
+}
+
+func TestNewBundleCache(t *testing.T) {
+	previous, found := os.LookupEnv(document.BundleCacheDirEnv)
+	defer func() {
+		if found {
+			require.NoError(t, os.Setenv(document.BundleCacheDirEnv, previous))
+		} else {
+			require.NoError(t, os.Unsetenv(document.BundleCacheDirEnv))
+		}
+	}()
+
+	require.NoError(t, os.Setenv(document.BundleCacheDirEnv, "/tmp/bundles"))
+	cache, err := document.NewBundleCache()
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/bundles", cache.Dir)
+
+	require.NoError(t, os.Unsetenv(document.BundleCacheDirEnv))
+	cache, err = document.NewBundleCache()
+	require.NoError(t, err)
+	homeDir, err := os.UserHomeDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(homeDir, ".airship", document.BundleCacheDir), cache.Dir)
+}
+
+// makeCachedManifest returns the kustomization root with a single ConfigMap
+// along with the cache of its bundles
+func makeCachedManifest(t *testing.T, value string) (string, *document.BundleCache, func(*testing.T)) {
+	t.Helper()
+	tempDir, cleanup := testutil.TempDir(t, "airship-bundle-cache")
+	kustomizePath := filepath.Join(tempDir, "manifest")
+	require.NoError(t, os.Mkdir(kustomizePath, 0755))
+	writeManifestFile(t, kustomizePath, "kustomization.yaml", "resources:\n- configmap.yaml\n")
+	writeManifestFile(t, kustomizePath, "configmap.yaml", fmt.Sprintf(configMapTemplate, value))
+	return kustomizePath, &document.BundleCache{Dir: filepath.Join(tempDir, "cache")}, cleanup
+}
+
+func writeManifestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+}
+
+func assertCachedValue(t *testing.T, bundle document.Bundle, expected string) {
+	t.Helper()
+	doc, err := bundle.GetByName("cached")
+	require.NoError(t, err)
+	value, err := doc.GetString("data.value")
+	require.NoError(t, err)
+	assert.Equal(t, expected, value)
+}
+
+func cacheEntries(t *testing.T, cache *document.BundleCache) []string {
+	t.Helper()
+	entries, err := filepath.Glob(filepath.Join(cache.Dir, "*.json"))
+	require.NoError(t, err)
+	return entries
+}
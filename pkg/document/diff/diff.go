@@ -0,0 +1,213 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+// ID identifies document by its api version, kind, namespace and name
+type ID struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// String returns ID in the form of <apiVersion> <kind> [<namespace>/]<name>
+func (id ID) String() string {
+	name := id.Name
+	if id.Namespace != "" {
+		name = id.Namespace + "/" + id.Name
+	}
+	return fmt.Sprintf("%s %s %s", id.APIVersion, id.Kind, name)
+}
+
+// FieldDiff describes a single changed field of the document, From is
+// omitted for added fields and To is omitted for removed ones
+type FieldDiff struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// Change holds field level differences of the document present in both bundles
+type Change struct {
+	ID
+	Fields []FieldDiff `json:"fields"`
+}
+
+// Result contains documents added, removed and changed between two bundles
+type Result struct {
+	Added   []ID     `json:"added"`
+	Removed []ID     `json:"removed"`
+	Changed []Change `json:"changed"`
+}
+
+// Empty returns true if bundles are semantically equal
+func (r *Result) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+// Compare reports documents added, removed and changed in bundle to comparing
+// with bundle from. Documents are matched by ID and compared field by field,
+// so order of documents and keys does not affect the result
+func Compare(from, to document.Bundle) (*Result, error) {
+	fromDocs, err := indexDocuments(from)
+	if err != nil {
+		return nil, err
+	}
+	toDocs, err := indexDocuments(to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Added:   []ID{},
+		Removed: []ID{},
+		Changed: []Change{},
+	}
+	for _, id := range sortedIDs(fromDocs) {
+		toDoc, found := toDocs[id]
+		if !found {
+			result.Removed = append(result.Removed, id)
+			continue
+		}
+		if fields := compareValues("", fromDocs[id], toDoc, nil); len(fields) > 0 {
+			result.Changed = append(result.Changed, Change{ID: id, Fields: fields})
+		}
+	}
+	for _, id := range sortedIDs(toDocs) {
+		if _, found := fromDocs[id]; !found {
+			result.Added = append(result.Added, id)
+		}
+	}
+	return result, nil
+}
+
+// indexDocuments returns generic representation of bundle documents by their IDs
+func indexDocuments(bundle document.Bundle) (map[ID]interface{}, error) {
+	docs, err := bundle.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[ID]interface{}, len(docs))
+	for _, doc := range docs {
+		id, err := NewID(doc)
+		if err != nil {
+			return nil, err
+		}
+		data, err := doc.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var obj interface{}
+		if err = json.Unmarshal(data, &obj); err != nil {
+			return nil, err
+		}
+		index[id] = obj
+	}
+	return index, nil
+}
+
+// NewID returns ID of the document
+func NewID(doc document.Document) (ID, error) {
+	apiVersion, err := doc.GetString("apiVersion")
+	if err != nil {
+		return ID{}, err
+	}
+	return ID{
+		APIVersion: apiVersion,
+		Kind:       doc.GetKind(),
+		Namespace:  doc.GetNamespace(),
+		Name:       doc.GetName(),
+	}, nil
+}
+
+func sortedIDs(index map[ID]interface{}) []ID {
+	ids := make([]ID, 0, len(index))
+	for id := range index {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids
+}
+
+// compareValues recursively compares generic JSON values and appends
+// differences found to diffs
+func compareValues(path string, from, to interface{}, diffs []FieldDiff) []FieldDiff {
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		toValue, ok := to.(map[string]interface{})
+		if !ok {
+			return append(diffs, FieldDiff{Path: path, From: from, To: to})
+		}
+		for _, key := range unionKeys(fromValue, toValue) {
+			fromField, inFrom := fromValue[key]
+			toField, inTo := toValue[key]
+			fieldPath := joinPath(path, key)
+			switch {
+			case !inFrom:
+				diffs = append(diffs, FieldDiff{Path: fieldPath, To: toField})
+			case !inTo:
+				diffs = append(diffs, FieldDiff{Path: fieldPath, From: fromField})
+			default:
+				diffs = compareValues(fieldPath, fromField, toField, diffs)
+			}
+		}
+		return diffs
+	case []interface{}:
+		toValue, ok := to.([]interface{})
+		if !ok {
+			return append(diffs, FieldDiff{Path: path, From: from, To: to})
+		}
+		for i := 0; i < len(fromValue) || i < len(toValue); i++ {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(fromValue):
+				diffs = append(diffs, FieldDiff{Path: itemPath, To: toValue[i]})
+			case i >= len(toValue):
+				diffs = append(diffs, FieldDiff{Path: itemPath, From: fromValue[i]})
+			default:
+				diffs = compareValues(itemPath, fromValue[i], toValue[i], diffs)
+			}
+		}
+		return diffs
+	default:
+		if !reflect.DeepEqual(from, to) {
+			return append(diffs, FieldDiff{Path: path, From: from, To: to})
+		}
+		return diffs
+	}
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, found := a[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinPath appends key to the field path, keys containing dots or brackets
+// (e.g. annotation names) are quoted
+func joinPath(path, key string) string {
+	if strings.ContainsAny(key, ".[]") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
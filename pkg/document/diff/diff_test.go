@@ -0,0 +1,112 @@
+package diff_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document/diff"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const expectedText = `+ v1 ConfigMap test/added-config
+- v1 Secret test/removed-secret
+~ apps/v1 Deployment test/test-deployment
+    metadata.annotations: {"airshipit.org/component":"dashboard"} -> <none>
+    spec.replicas: 1 -> 3
+    spec.template.spec.containers[0].image: "dashboard:v1.0.0" -> "dashboard:v1.1.0"
+1 added, 1 removed, 1 changed
+`
+
+func TestCompare(t *testing.T) {
+	from := testutil.NewTestBundle(t, "testdata/from")
+	to := testutil.NewTestBundle(t, "testdata/to")
+
+	result, err := diff.Compare(from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, []diff.ID{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "test", Name: "added-config"}},
+		result.Added)
+	assert.Equal(t, []diff.ID{{APIVersion: "v1", Kind: "Secret", Namespace: "test", Name: "removed-secret"}},
+		result.Removed)
+	require.Len(t, result.Changed, 1)
+	change := result.Changed[0]
+	assert.Equal(t, "apps/v1 Deployment test/test-deployment", change.ID.String())
+	assert.Equal(t, []diff.FieldDiff{
+		{Path: "metadata.annotations", From: map[string]interface{}{"airshipit.org/component": "dashboard"}},
+		{Path: "spec.replicas", From: float64(1), To: float64(3)},
+		{Path: "spec.template.spec.containers[0].image", From: "dashboard:v1.0.0", To: "dashboard:v1.1.0"},
+	}, change.Fields)
+
+	result, err = diff.Compare(from, from)
+	require.NoError(t, err)
+	assert.True(t, result.Empty())
+}
+
+func TestResultWrite(t *testing.T) {
+	result, err := diff.Compare(testutil.NewTestBundle(t, "testdata/from"), testutil.NewTestBundle(t, "testdata/to"))
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, result.Write(out, diff.TextFormat))
+	assert.Equal(t, expectedText, out.String())
+
+	out.Reset()
+	require.NoError(t, result.Write(out, diff.JSONFormat))
+	decoded := &diff.Result{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), decoded))
+	assert.Equal(t, result, decoded)
+
+	out.Reset()
+	require.NoError(t, result.Write(out, diff.YAMLFormat))
+	assert.Contains(t, out.String(), "path: spec.replicas")
+
+	assert.Equal(t, diff.ErrUnknownOutputFormat{Format: "xml"}, result.Write(out, "xml"))
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    *diff.Settings
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "compare paths",
+			settings: &diff.Settings{FromPath: "testdata/from", ToPath: "testdata/to"},
+			expected: expectedText,
+		},
+		{
+			name:        "conflicting from sources",
+			settings:    &diff.Settings{FromRef: "HEAD", FromPath: "testdata/from"},
+			expectedErr: diff.ErrConflictingSources{Side: "from"},
+		},
+		{
+			name:        "conflicting to sources",
+			settings:    &diff.Settings{FromPath: "testdata/from", ToRef: "HEAD", ToPath: "testdata/to"},
+			expectedErr: diff.ErrConflictingSources{Side: "to"},
+		},
+		{
+			name:        "unknown output format",
+			settings:    &diff.Settings{FromPath: "testdata/from", ToPath: "testdata/to", Output: "xml"},
+			expectedErr: diff.ErrUnknownOutputFormat{Format: "xml"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := tt.settings.Diff(out)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrConflictingSources returned if both git revision and path are set for the same side of the diff
+type ErrConflictingSources struct {
+	Side string
+}
+
+func (e ErrConflictingSources) Error() string {
+	return fmt.Sprintf("Only one of --%[1]s-ref and --%[1]s-path can be specified", e.Side)
+}
+
+// ErrUnknownOutputFormat returned if the diff can not be written in the requested format
+type ErrUnknownOutputFormat struct {
+	Format string
+}
+
+func (e ErrUnknownOutputFormat) Error() string {
+	return fmt.Sprintf("Unknown output format %q, supported formats: %s",
+		e.Format, strings.Join(OutputFormats(), ", "))
+}
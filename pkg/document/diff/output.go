@@ -0,0 +1,92 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	utilyaml "opendev.org/airship/airshipctl/pkg/util/yaml"
+)
+
+// Supported formats of the diff output
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+	YAMLFormat = "yaml"
+)
+
+// OutputFormats returns formats the result can be written in
+func OutputFormats() []string {
+	return []string{TextFormat, JSONFormat, YAMLFormat}
+}
+
+func validFormat(format string) bool {
+	if format == "" {
+		return true
+	}
+	for _, supported := range OutputFormats() {
+		if format == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes the result to out in the requested format
+func (r *Result) Write(out io.Writer, format string) error {
+	switch format {
+	case TextFormat, "":
+		return r.writeText(out)
+	case JSONFormat:
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	case YAMLFormat:
+		return utilyaml.WriteOut(out, r)
+	default:
+		return ErrUnknownOutputFormat{Format: format}
+	}
+}
+
+// writeText writes the result in a human readable form, one line per
+// added (+), removed (-) and changed (~) document followed by its changed
+// fields, and a summary line at the end
+func (r *Result) writeText(out io.Writer) error {
+	for _, id := range r.Added {
+		if _, err := fmt.Fprintf(out, "+ %s\n", id); err != nil {
+			return err
+		}
+	}
+	for _, id := range r.Removed {
+		if _, err := fmt.Fprintf(out, "- %s\n", id); err != nil {
+			return err
+		}
+	}
+	for _, change := range r.Changed {
+		if _, err := fmt.Fprintf(out, "~ %s\n", change.ID); err != nil {
+			return err
+		}
+		for _, field := range change.Fields {
+			_, err := fmt.Fprintf(out, "    %s: %s -> %s\n", field.Path, formatValue(field.From), formatValue(field.To))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(out, "%d added, %d removed, %d changed\n", len(r.Added), len(r.Removed), len(r.Changed))
+	return err
+}
+
+func formatValue(value interface{}) string {
+	if value == nil {
+		return "<none>"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,117 @@
+package diff
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/repo"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// DefaultFromRef is the revision compared from if no source is specified
+const DefaultFromRef = "HEAD"
+
+// Diff builds both bundles, compares them and writes the result to out
+func (s *Settings) Diff(out io.Writer) error {
+	if s.FromRef != "" && s.FromPath != "" {
+		return ErrConflictingSources{Side: "from"}
+	}
+	if s.ToRef != "" && s.ToPath != "" {
+		return ErrConflictingSources{Side: "to"}
+	}
+	if !validFormat(s.Output) {
+		return ErrUnknownOutputFormat{Format: s.Output}
+	}
+
+	fromRef := s.FromRef
+	if fromRef == "" && s.FromPath == "" {
+		fromRef = DefaultFromRef
+	}
+	from, err := s.bundle(fromRef, s.FromPath)
+	if err != nil {
+		return err
+	}
+	to, err := s.bundle(s.ToRef, s.ToPath)
+	if err != nil {
+		return err
+	}
+
+	result, err := Compare(from, to)
+	if err != nil {
+		return err
+	}
+	return result.Write(out, s.Output)
+}
+
+// bundle builds the bundle from kustomization path if one is set, from the
+// revision of the primary repository if one is set, or from the entrypoint
+// of the current context otherwise
+func (s *Settings) bundle(revision, path string) (document.Bundle, error) {
+	if path != "" {
+		return document.NewBundleByPath(path)
+	}
+
+	conf := s.Config()
+	if err := conf.EnsureComplete(); err != nil {
+		return nil, err
+	}
+	entryPoint, err := conf.CurrentContextEntryPoint(s.ClusterType, s.Phase)
+	if err != nil {
+		return nil, err
+	}
+	if revision == "" {
+		return document.NewBundleByPath(entryPoint)
+	}
+
+	manifest, err := conf.CurrentContextManifest()
+	if err != nil {
+		return nil, err
+	}
+	tmpDir, err := ioutil.TempDir("", "airshipctl-diff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err = exportManifest(manifest, revision, tmpDir); err != nil {
+		return nil, err
+	}
+	return document.NewBundleByPath(filepath.Join(tmpDir, manifest.SubPath, s.ClusterType, s.Phase))
+}
+
+// exportManifest exports the primary repository of the manifest at revision
+// to dst and links the rest of repositories there as they are on disk, so
+// references between repositories are resolved the same way as in TargetPath
+func exportManifest(manifest *config.Manifest, revision, dst string) error {
+	for name, repoConfig := range manifest.Repositories {
+		dirName := util.GitDirNameFromURL(repoConfig.URL())
+		if name != manifest.PrimaryRepositoryName {
+			target, err := filepath.Abs(filepath.Join(manifest.TargetPath, dirName))
+			if err != nil {
+				return err
+			}
+			if err = os.Symlink(target, filepath.Join(dst, dirName)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		repository, err := repo.NewRepository(manifest.TargetPath, repoConfig)
+		if err != nil {
+			return err
+		}
+		if err = repository.Open(); err != nil {
+			return err
+		}
+		err = repository.Export(revision, filepath.Join(dst, repository.Name))
+		repository.Driver.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package diff
+
+import (
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// Settings for document diff
+type Settings struct {
+	*environment.AirshipCTLSettings
+	// ClusterType is the cluster type whose entrypoint is compared
+	ClusterType string
+	// Phase is the phase within the cluster type to be compared
+	Phase string
+	// FromRef is a git revision of the primary repository to compare from,
+	// HEAD is used if neither FromRef nor FromPath is set
+	FromRef string
+	// FromPath is a kustomization directory to compare from
+	FromPath string
+	// ToRef is a git revision of the primary repository to compare to,
+	// the working tree is used if neither ToRef nor ToPath is set
+	ToRef string
+	// ToPath is a kustomization directory to compare to
+	ToPath string
+	// Output is a format the result is written in, e.g. text, json or yaml
+	Output string
+}
@@ -1,5 +1,10 @@
 package document
 
+import (
+	"fmt"
+	"strings"
+)
+
 // GetBMHNetworkData retrieves the associated network data string
 // for the bmh document supplied from the bundle supplied
 func GetBMHNetworkData(bmh Document, bundle Bundle) (string, error) {
@@ -58,3 +63,117 @@ func GetBMHBMCCredentials(bmh Document, bundle Bundle) (username string, passwor
 	// extract the username and password from them
 	return username, password, nil
 }
+
+// BMHProblem describes a dangling, ambiguous or duplicate reference of the
+// BareMetalHost document
+type BMHProblem struct {
+	// Host is the namespace and the name of the BareMetalHost
+	Host    string
+	Field   string
+	Message string
+}
+
+func (p BMHProblem) String() string {
+	return fmt.Sprintf("%s %s: %s: %s", BareMetalHostKind, p.Host, p.Field, p.Message)
+}
+
+// CheckBMHReferences resolves network data, user data and BMC credentials
+// references of every BareMetalHost in the bundle the same way bootstrap does
+// and returns all references that are dangling or ambiguous, as well as BMC
+// addresses and boot MAC addresses shared by several hosts
+func CheckBMHReferences(bundle Bundle) ([]BMHProblem, error) {
+	hosts, err := bundle.Select(NewSelector().ByKind(BareMetalHostKind))
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []BMHProblem
+	bmcAddresses := make(map[string]string)
+	bootMACAddresses := make(map[string]string)
+	for _, bmh := range hosts {
+		host := bmh.GetName()
+		if bmh.GetNamespace() != "" {
+			host = bmh.GetNamespace() + "/" + host
+		}
+		report := func(field, format string, args ...interface{}) {
+			problems = append(problems, BMHProblem{Host: host, Field: field, Message: fmt.Sprintf(format, args...)})
+		}
+
+		for _, ref := range []struct {
+			field       string
+			newSelector func(Document) (Selector, error)
+		}{
+			{field: "spec.networkData", newSelector: NewNetworkDataSelector},
+			{field: "spec.userData", newSelector: NewUserDataSelector},
+		} {
+			// both references are optional
+			if _, err = bmh.GetMap(ref.field); err != nil {
+				continue
+			}
+			selector, err := ref.newSelector(bmh)
+			if err != nil {
+				report(ref.field, "reference must have name and namespace")
+				continue
+			}
+			msg, err := resolveReference(bundle, selector)
+			if err != nil {
+				return nil, err
+			}
+			if msg != "" {
+				report(ref.field, "%s", msg)
+			}
+		}
+
+		credentialsName, err := bmh.GetString("spec.bmc.credentialsName")
+		if err != nil {
+			report("spec.bmc.credentialsName", "field is missing")
+		} else {
+			msg, err := resolveReference(bundle, NewBMCCredentialsSelector(credentialsName))
+			if err != nil {
+				return nil, err
+			}
+			if msg != "" {
+				report("spec.bmc.credentialsName", "%s", msg)
+			}
+		}
+
+		if address, err := GetBMHBMCAddress(bmh); err == nil {
+			if other, found := bmcAddresses[address]; found {
+				report("spec.bmc.address", "address %q is also used by %s", address, other)
+			} else {
+				bmcAddresses[address] = host
+			}
+		}
+		if mac, err := bmh.GetString("spec.bootMACAddress"); err == nil {
+			key := strings.ToLower(mac)
+			if other, found := bootMACAddresses[key]; found {
+				report("spec.bootMACAddress", "address %q is also used by %s", mac, other)
+			} else {
+				bootMACAddresses[key] = host
+			}
+		}
+	}
+	return problems, nil
+}
+
+// resolveReference returns description of the problem if the selector
+// matches none or more than one document
+func resolveReference(bundle Bundle, selector Selector) (string, error) {
+	docs, err := bundle.Select(selector)
+	if err != nil {
+		return "", err
+	}
+
+	target := fmt.Sprintf("%s %q", selector.Kind, selector.Name)
+	if selector.Namespace != "" {
+		target = fmt.Sprintf("%s in namespace %q", target, selector.Namespace)
+	}
+	switch len(docs) {
+	case 0:
+		return target + " not found", nil
+	case 1:
+		return "", nil
+	default:
+		return fmt.Sprintf("%s is ambiguous, %d documents found", target, len(docs)), nil
+	}
+}
@@ -53,3 +53,28 @@ func TestDocHelpers(t *testing.T) {
 		assert.Equal(bmcPassword, "password")
 	})
 }
+
+func TestCheckBMHReferences(t *testing.T) {
+	bundle := testutil.NewTestBundle(t, "testdata/bmhcheck")
+
+	problems, err := document.CheckBMHReferences(bundle)
+	require.NoError(t, err)
+
+	actual := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		actual = append(actual, problem.String())
+	}
+	assert.ElementsMatch(t, []string{
+		`BareMetalHost metal3/node-1: spec.networkData: Secret "missing-networkdata" in namespace "metal3" not found`,
+		`BareMetalHost metal3/node-1: spec.bmc.credentialsName: Secret "shared-bmc" is ambiguous, 2 documents found`,
+		`BareMetalHost metal3/node-1: spec.bmc.address: address ` +
+			`"redfish+https://192.168.111.1/v1/Redfish/Foo/Bar" is also used by metal3/node-0`,
+		`BareMetalHost metal3/node-1: spec.bootMACAddress: address "00:3B:8B:0C:EC:8B" is also used by metal3/node-0`,
+		`BareMetalHost metal3/node-2: spec.userData: reference must have name and namespace`,
+		`BareMetalHost metal3/node-2: spec.bmc.credentialsName: field is missing`,
+	}, actual)
+
+	problems, err = document.CheckBMHReferences(testutil.NewTestBundle(t, "testdata/dochelper"))
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
@@ -7,6 +7,8 @@ import (
 // Factory holds document data
 type Factory struct {
 	resource.Resource
+
+	origin *Origin
 }
 
 // Document interface
@@ -28,6 +30,7 @@ type Document interface {
 	GetStringSlice(path string) ([]string, error)
 	Label(key, value string)
 	MarshalJSON() ([]byte, error)
+	Origin() *Origin
 }
 
 // Factory implements Document
@@ -142,6 +145,12 @@ func (d *Factory) SetKustomizeResource(r *resource.Resource) error {
 	return nil
 }
 
+// Origin returns where the document comes from, it is nil if the document
+// isn't loaded from a file of the bundle, e.g. generated by kustomize
+func (d *Factory) Origin() *Origin {
+	return d.origin
+}
+
 // NewDocument is a convenience method to construct a new Document.  Although
 // an error is unlikely at this time, this provides some future proofing for
 // when we want more strict airship specific validation of documents getting
@@ -0,0 +1,125 @@
+package document
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/util"
+	utilyaml "opendev.org/airship/airshipctl/pkg/util/yaml"
+)
+
+// Supported output formats
+const (
+	YAMLFormat      = "yaml"
+	JSONFormat      = "json"
+	JSONLinesFormat = "jsonl"
+	TableFormat     = "table"
+)
+
+// Encoder writes documents to the output in a particular format
+type Encoder interface {
+	Encode(out io.Writer, docs []Document) error
+}
+
+// EncoderFunc allows to use ordinary function as Encoder
+type EncoderFunc func(out io.Writer, docs []Document) error
+
+// Encode calls f(out, docs)
+func (f EncoderFunc) Encode(out io.Writer, docs []Document) error {
+	return f(out, docs)
+}
+
+var encoders = map[string]Encoder{
+	YAMLFormat:      EncoderFunc(encodeYAML),
+	JSONFormat:      EncoderFunc(encodeJSON),
+	JSONLinesFormat: EncoderFunc(encodeJSONLines),
+	TableFormat:     EncoderFunc(encodeTable),
+}
+
+// RegisterEncoder makes encoder available by the format name, encoder
+// previously registered with the same name is replaced
+func RegisterEncoder(format string, encoder Encoder) {
+	encoders[format] = encoder
+}
+
+// GetEncoder returns encoder registered for the format
+func GetEncoder(format string) (Encoder, error) {
+	encoder, found := encoders[format]
+	if !found {
+		return nil, ErrUnknownOutputFormat{Format: format, Supported: OutputFormats()}
+	}
+	return encoder, nil
+}
+
+// OutputFormats returns sorted list of registered output formats
+func OutputFormats() []string {
+	formats := make([]string, 0, len(encoders))
+	for format := range encoders {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// encodeYAML writes documents as multi-document YAML stream
+func encodeYAML(out io.Writer, docs []Document) error {
+	for _, doc := range docs {
+		if err := utilyaml.WriteOut(out, doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeJSON writes documents as indented JSON array
+func encodeJSON(out io.Writer, docs []Document) error {
+	if docs == nil {
+		docs = []Document{}
+	}
+	data, err := json.MarshalIndent(docs, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+// encodeJSONLines writes every document as JSON object on a separate line
+func encodeJSONLines(out io.Writer, docs []Document) error {
+	for _, doc := range docs {
+		data, err := doc.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintln(out, string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeTable writes kind, namespace, name and labels of documents as a table
+func encodeTable(out io.Writer, docs []Document) error {
+	w := util.NewTabWriter(out)
+	fmt.Fprintln(w, "KIND\tNAMESPACE\tNAME\tLABELS")
+	for _, doc := range docs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", doc.GetKind(), doc.GetNamespace(), doc.GetName(), labelsString(doc))
+	}
+	return w.Flush()
+}
+
+func labelsString(doc Document) string {
+	labels := doc.GetLabels()
+	if len(labels) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
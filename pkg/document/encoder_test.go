@@ -0,0 +1,113 @@
+package document_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestEncoders(t *testing.T) {
+	bundle := testutil.NewTestBundle(t, "testdata/order")
+
+	t.Run("yaml", func(t *testing.T) {
+		encoder, err := document.GetEncoder(document.YAMLFormat)
+		require.NoError(t, err)
+
+		var yamlOut, writeOut bytes.Buffer
+		require.NoError(t, bundle.Encode(&yamlOut, encoder))
+		require.NoError(t, bundle.Write(&writeOut))
+		assert.Equal(t, writeOut.String(), yamlOut.String())
+		assert.Equal(t, 3, strings.Count(yamlOut.String(), "---\n"))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		encoder, err := document.GetEncoder(document.JSONFormat)
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		require.NoError(t, bundle.Encode(&out, encoder))
+
+		var docs []map[string]interface{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &docs))
+		require.Len(t, docs, 3)
+		assert.Equal(t, "Namespace", docs[0]["kind"])
+		assert.Equal(t, "Deployment", docs[2]["kind"])
+	})
+
+	t.Run("json empty", func(t *testing.T) {
+		encoder, err := document.GetEncoder(document.JSONFormat)
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		require.NoError(t, encoder.Encode(&out, nil))
+		assert.Equal(t, "[]\n", out.String())
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		encoder, err := document.GetEncoder(document.JSONLinesFormat)
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		require.NoError(t, bundle.Encode(&out, encoder))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 3)
+		for _, line := range lines {
+			var doc map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(line), &doc))
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		encoder, err := document.GetEncoder(document.TableFormat)
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		require.NoError(t, bundle.Encode(&out, encoder))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 4)
+		assert.Equal(t, []string{"KIND", "NAMESPACE", "NAME", "LABELS"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{
+			"Deployment",
+			"argo-namespace",
+			"workflow-controller",
+			"app=workflow-controller,arbitrary-label=some-label",
+		}, strings.Fields(lines[3]))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := document.GetEncoder("xml")
+		assert.Equal(t, document.ErrUnknownOutputFormat{
+			Format:    "xml",
+			Supported: document.OutputFormats(),
+		}, err)
+	})
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	document.RegisterEncoder("names", document.EncoderFunc(func(out io.Writer, docs []document.Document) error {
+		for _, doc := range docs {
+			if _, err := io.WriteString(out, doc.GetName()+"\n"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	assert.Contains(t, document.OutputFormats(), "names")
+
+	encoder, err := document.GetEncoder("names")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, testutil.NewTestBundle(t, "testdata/order").Encode(&out, encoder))
+	assert.Equal(t, "argo-namespace\nworkflows.argoproj.io\nworkflow-controller\n", out.String())
+}
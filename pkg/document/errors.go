@@ -2,6 +2,7 @@ package document
 
 import (
 	"fmt"
+	"strings"
 )
 
 // ErrDocNotFound returned if desired document not found
@@ -42,3 +43,63 @@ func (e ErrDocumentMalformed) Error() string {
 func (e ErrMultipleDocsFound) Error() string {
 	return fmt.Sprintf("Document filtered by selector %v found more than one document", e.Selector)
 }
+
+// ErrUnknownOutputFormat returned if there is no encoder registered for the output format
+type ErrUnknownOutputFormat struct {
+	Format    string
+	Supported []string
+}
+
+func (e ErrUnknownOutputFormat) Error() string {
+	return fmt.Sprintf("Unknown output format %q, supported formats: %s", e.Format, strings.Join(e.Supported, ", "))
+}
+
+// ErrBundleCacheOutdated returned if the cached bundle can't be used
+type ErrBundleCacheOutdated struct {
+	Reason string
+}
+
+func (e ErrBundleCacheOutdated) Error() string {
+	return fmt.Sprintf("Cached bundle is outdated: %s", e.Reason)
+}
+
+// ErrDocumentExists returned if the bundle already has a document with the id
+type ErrDocumentExists struct {
+	ID string
+}
+
+func (e ErrDocumentExists) Error() string {
+	return fmt.Sprintf("Document %s already exists in the bundle", e.ID)
+}
+
+// ErrUnexpectedDocumentCount returned if the data doesn't hold exactly one document
+type ErrUnexpectedDocumentCount struct {
+	Count int
+}
+
+func (e ErrUnexpectedDocumentCount) Error() string {
+	return fmt.Sprintf("Expected exactly one document, found %d", e.Count)
+}
+
+// ErrInvalidPatch returned if the patch is neither a strategic merge nor a
+// JSON 6902 patch
+type ErrInvalidPatch struct {
+	Err error
+}
+
+func (e ErrInvalidPatch) Error() string {
+	if e.Err == nil {
+		return "Patch must be either a partial document or a list of JSON 6902 operations"
+	}
+	return fmt.Sprintf("Invalid patch: %v", e.Err)
+}
+
+// ErrPatchFailed returned if the patch can't be applied to the document
+type ErrPatchFailed struct {
+	Document string
+	Err      error
+}
+
+func (e ErrPatchFailed) Error() string {
+	return fmt.Sprintf("Failed to patch document %s: %v", e.Document, e.Err)
+}
@@ -0,0 +1,14 @@
+package filter
+
+import "fmt"
+
+// ErrInvalidExpression returned if filter expression can't be parsed
+type ErrInvalidExpression struct {
+	Expression string
+	Position   int
+	Message    string
+}
+
+func (e ErrInvalidExpression) Error() string {
+	return fmt.Sprintf("invalid filter expression %q at position %d: %s", e.Expression, e.Position, e.Message)
+}
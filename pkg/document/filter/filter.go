@@ -0,0 +1,223 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+// Expression is a compiled logical expression used to filter documents,
+// e.g. kind=Secret and not label(airshipit.org/phase=initinfra)
+//
+// Expression supports and, or, not operators, parentheses and following predicates:
+//     kind=<kind>, apiVersion=<group/version>, name=<name>, namespace=<namespace>
+//     label(<label selector>), annotation(<annotation selector>)
+//     field(<path>)[=<value>]
+// every attribute and field comparison can be negated using != instead of =,
+// values containing spaces or special characters should be quoted
+type Expression struct {
+	raw  string
+	root node
+}
+
+// Parse compiles raw string into filter expression
+func Parse(raw string) (*Expression, error) {
+	p := &parser{lex: &lexer{input: raw}}
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{raw: raw, root: root}, nil
+}
+
+// Select returns all documents of the bundle matching the expression
+func (e *Expression) Select(bundle document.Bundle) ([]document.Document, error) {
+	docs, err := bundle.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+	return e.Filter(bundle, docs)
+}
+
+// Filter returns documents from docs matching the expression, docs are
+// expected to be a part of the bundle expression is evaluated against
+func (e *Expression) Filter(bundle document.Bundle, docs []document.Document) ([]document.Document, error) {
+	matched, err := e.root.match(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]document.Document, 0, len(docs))
+	for _, doc := range docs {
+		id, err := documentID(doc)
+		if err != nil {
+			return nil, err
+		}
+		if matched[id] {
+			result = append(result, doc)
+		}
+	}
+	return result, nil
+}
+
+// String returns canonical representation of the expression
+func (e *Expression) String() string {
+	return e.root.String()
+}
+
+// idSet is a set of document identifiers
+type idSet map[string]bool
+
+// node is an element of expression tree
+type node interface {
+	match(document.Bundle) (idSet, error)
+	String() string
+}
+
+// selectorNode matches documents using document selector
+type selectorNode struct {
+	selector document.Selector
+	text     string
+}
+
+func (n selectorNode) match(bundle document.Bundle) (idSet, error) {
+	docs, err := bundle.Select(n.selector)
+	if err != nil {
+		return nil, err
+	}
+	return newIDSet(docs)
+}
+
+func (n selectorNode) String() string {
+	return n.text
+}
+
+// fieldNode matches documents containing field at path, if compare is set
+// string representation of field value must be equal to value
+type fieldNode struct {
+	path    string
+	value   string
+	compare bool
+}
+
+func (n fieldNode) match(bundle document.Bundle) (idSet, error) {
+	selected, err := bundle.SelectByFieldValue(n.path, func(v interface{}) bool {
+		return !n.compare || fmt.Sprint(v) == n.value
+	})
+	if err != nil {
+		return nil, err
+	}
+	docs, err := selected.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+	return newIDSet(docs)
+}
+
+func (n fieldNode) String() string {
+	if n.path == apiVersionPredicate {
+		return apiVersionPredicate + "=" + n.value
+	}
+	if !n.compare {
+		return "field(" + n.path + ")"
+	}
+	return "field(" + n.path + ")=" + n.value
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) match(bundle document.Bundle) (idSet, error) {
+	docs, err := bundle.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+	all, err := newIDSet(docs)
+	if err != nil {
+		return nil, err
+	}
+	excluded, err := n.operand.match(bundle)
+	if err != nil {
+		return nil, err
+	}
+	for id := range excluded {
+		delete(all, id)
+	}
+	return all, nil
+}
+
+func (n notNode) String() string {
+	return notOperator + " " + n.operand.String()
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n andNode) match(bundle document.Bundle) (idSet, error) {
+	left, err := n.left.match(bundle)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.match(bundle)
+	if err != nil {
+		return nil, err
+	}
+	result := idSet{}
+	for id := range left {
+		if right[id] {
+			result[id] = true
+		}
+	}
+	return result, nil
+}
+
+func (n andNode) String() string {
+	return "(" + n.left.String() + " " + andOperator + " " + n.right.String() + ")"
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n orNode) match(bundle document.Bundle) (idSet, error) {
+	left, err := n.left.match(bundle)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.match(bundle)
+	if err != nil {
+		return nil, err
+	}
+	for id := range right {
+		left[id] = true
+	}
+	return left, nil
+}
+
+func (n orNode) String() string {
+	return "(" + n.left.String() + " " + orOperator + " " + n.right.String() + ")"
+}
+
+func newIDSet(docs []document.Document) (idSet, error) {
+	set := make(idSet, len(docs))
+	for _, doc := range docs {
+		id, err := documentID(doc)
+		if err != nil {
+			return nil, err
+		}
+		set[id] = true
+	}
+	return set, nil
+}
+
+// documentID identifies document within the bundle by api version, kind, namespace and name
+func documentID(doc document.Document) (string, error) {
+	apiVersion, err := doc.GetString(apiVersionPredicate)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{apiVersion, doc.GetKind(), doc.GetNamespace(), doc.GetName()}, "|"), nil
+}
@@ -0,0 +1,127 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document/filter"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{
+			raw:      "kind=Secret",
+			expected: "kind=Secret",
+		},
+		{
+			raw:      "kind=Secret and not label(airshipit.org/phase=initinfra)",
+			expected: "(kind=Secret and not label(airshipit.org/phase=initinfra))",
+		},
+		{
+			raw:      "kind=Secret or kind=Deployment and namespace=infra",
+			expected: "(kind=Secret or (kind=Deployment and namespace=infra))",
+		},
+		{
+			raw:      "(kind=Secret OR kind=Deployment) AND namespace!=infra",
+			expected: "((kind=Secret or kind=Deployment) and not namespace=infra)",
+		},
+		{
+			raw:      "label(tier in (web, db)) and field(spec.replicas)='3'",
+			expected: "(label(tier in (web, db)) and field(spec.replicas)=3)",
+		},
+		{
+			raw:      "field(spec.online) or apiVersion=\"apps/v1\"",
+			expected: "(field(spec.online) or apiVersion=apps/v1)",
+		},
+	}
+
+	for _, tt := range tests {
+		expr, err := filter.Parse(tt.raw)
+		require.NoError(t, err, tt.raw)
+		assert.Equal(t, tt.expected, expr.String())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	invalid := []string{
+		"",
+		"kind",
+		"kind=",
+		"kind=Secret and",
+		"(kind=Secret",
+		"kind=Secret)",
+		"unknown=value",
+		"label(a=b",
+		"label()",
+		"kind=Secret ! kind=Deployment",
+		"name='unterminated",
+	}
+
+	for _, raw := range invalid {
+		_, err := filter.Parse(raw)
+		assert.Error(t, err, raw)
+		assert.IsType(t, filter.ErrInvalidExpression{}, err, raw)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	bundle := testutil.NewTestBundle(t, "testdata")
+
+	tests := []struct {
+		raw      string
+		expected []string
+	}{
+		{
+			raw:      "kind=Secret",
+			expected: []string{"infra-secret", "site-secret"},
+		},
+		{
+			raw:      "kind=Secret and not label(airshipit.org/phase=initinfra)",
+			expected: []string{"site-secret"},
+		},
+		{
+			raw:      "label(airshipit.org/phase=initinfra) or annotation(airshipit.org/owner=site)",
+			expected: []string{"infra-secret", "site-secret", "infra-deployment"},
+		},
+		{
+			raw:      "apiVersion=v1",
+			expected: []string{"infra-secret", "site-secret"},
+		},
+		{
+			raw:      "namespace=site and not kind=Secret",
+			expected: []string{"node-1"},
+		},
+		{
+			raw:      "field(spec.replicas)=3 or field(spec.online)=true",
+			expected: []string{"infra-deployment", "node-1"},
+		},
+		{
+			raw:      "field(spec.replicas)",
+			expected: []string{"infra-deployment"},
+		},
+		{
+			raw:      "name=node-1 and kind=Secret",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		expr, err := filter.Parse(tt.raw)
+		require.NoError(t, err, tt.raw)
+
+		docs, err := expr.Select(bundle)
+		require.NoError(t, err, tt.raw)
+
+		names := make([]string, 0, len(docs))
+		for _, doc := range docs {
+			names = append(names, doc.GetName())
+		}
+		assert.ElementsMatch(t, tt.expected, names, tt.raw)
+	}
+}
@@ -0,0 +1,128 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenEqual
+	tokenNotEqual
+	tokenLParen
+	tokenRParen
+	// tokenFunc is a predicate with raw argument, e.g. label(a in (b, c))
+	tokenFunc
+)
+
+// functions lists predicates accepting raw argument in parentheses
+var functions = map[string]bool{
+	labelPredicate:      true,
+	annotationPredicate: true,
+	fieldPredicate:      true,
+}
+
+type token struct {
+	kind  tokenKind
+	value string
+	arg   string
+	pos   int
+}
+
+type lexer struct {
+	input string
+	pos   int
+}
+
+func (l *lexer) errorf(pos int, format string, a ...interface{}) error {
+	return ErrInvalidExpression{
+		Expression: l.input,
+		Position:   pos,
+		Message:    fmt.Sprintf(format, a...),
+	}
+}
+
+// next returns next token from the input
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(rune(l.input[l.pos])) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	switch c := l.input[l.pos]; {
+	case c == '(':
+		l.pos++
+		return token{kind: tokenLParen, value: "(", pos: start}, nil
+	case c == ')':
+		l.pos++
+		return token{kind: tokenRParen, value: ")", pos: start}, nil
+	case c == '=':
+		l.pos++
+		return token{kind: tokenEqual, value: "=", pos: start}, nil
+	case c == '!':
+		if l.pos+1 < len(l.input) && l.input[l.pos+1] == '=' {
+			l.pos += 2
+			return token{kind: tokenNotEqual, value: "!=", pos: start}, nil
+		}
+		return token{}, l.errorf(start, "unexpected character %q", c)
+	case c == '"' || c == '\'':
+		return l.readString(c)
+	default:
+		word := l.readWord()
+		if functions[word] && l.pos < len(l.input) && l.input[l.pos] == '(' {
+			arg, err := l.readArgument()
+			if err != nil {
+				return token{}, err
+			}
+			return token{kind: tokenFunc, value: word, arg: arg, pos: start}, nil
+		}
+		return token{kind: tokenWord, value: word, pos: start}, nil
+	}
+}
+
+// readWord reads characters until space, parenthesis, quote or comparison
+func (l *lexer) readWord() string {
+	start := l.pos
+	for l.pos < len(l.input) && !strings.ContainsRune(" \t\n\r()=!\"'", rune(l.input[l.pos])) {
+		l.pos++
+	}
+	return l.input[start:l.pos]
+}
+
+// readString reads quoted string, quote is the opening quotation character
+func (l *lexer) readString(quote byte) (token, error) {
+	start := l.pos
+	end := strings.IndexByte(l.input[start+1:], quote)
+	if end < 0 {
+		return token{}, l.errorf(start, "unterminated string")
+	}
+	l.pos = start + end + 2
+	return token{kind: tokenString, value: l.input[start+1 : start+end+1], pos: start}, nil
+}
+
+// readArgument reads everything between balanced parentheses
+func (l *lexer) readArgument() (string, error) {
+	start := l.pos
+	depth := 0
+	for ; l.pos < len(l.input); l.pos++ {
+		switch l.input[l.pos] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 {
+			l.pos++
+			return strings.TrimSpace(l.input[start+1 : l.pos-1]), nil
+		}
+	}
+	return "", l.errorf(start, "unbalanced parentheses")
+}
@@ -0,0 +1,222 @@
+package filter
+
+import (
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+// Supported predicates
+const (
+	kindPredicate       = "kind"
+	apiVersionPredicate = "apiVersion"
+	namePredicate       = "name"
+	namespacePredicate  = "namespace"
+	labelPredicate      = "label"
+	annotationPredicate = "annotation"
+	fieldPredicate      = "field"
+)
+
+// Logical operators
+const (
+	andOperator = "and"
+	orOperator  = "or"
+	notOperator = "not"
+)
+
+// parser builds expression tree using the following grammar
+//
+//     expression = term { "or" term }
+//     term       = factor { "and" factor }
+//     factor     = "not" factor | "(" expression ")" | predicate
+//     predicate  = attribute ( "=" | "!=" ) value
+//                | "label(" selector ")" | "annotation(" selector ")"
+//                | "field(" path ")" [ ( "=" | "!=" ) value ]
+//     attribute  = "kind" | "apiVersion" | "name" | "namespace"
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) isOperator(operator string) bool {
+	return p.tok.kind == tokenWord && strings.EqualFold(p.tok.value, operator)
+}
+
+func (p *parser) parse() (node, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenEOF {
+		return nil, p.lex.errorf(p.tok.pos, "expression is empty")
+	}
+	n, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, p.lex.errorf(p.tok.pos, "unexpected %q", p.tok.value)
+	}
+	return n, nil
+}
+
+func (p *parser) parseExpression() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator(orOperator) {
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator(andOperator) {
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseFactor() (node, error) {
+	switch {
+	case p.isOperator(notOperator):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	case p.tok.kind == tokenLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenRParen {
+			return nil, p.lex.errorf(p.tok.pos, "expected \")\"")
+		}
+		return n, p.advance()
+	case p.tok.kind == tokenFunc:
+		return p.parseFunc()
+	case p.tok.kind == tokenWord && !p.isOperator(andOperator) && !p.isOperator(orOperator):
+		return p.parseAttribute()
+	case p.tok.kind == tokenEOF:
+		return nil, p.lex.errorf(p.tok.pos, "unexpected end of expression")
+	default:
+		return nil, p.lex.errorf(p.tok.pos, "unexpected %q", p.tok.value)
+	}
+}
+
+func (p *parser) parseFunc() (node, error) {
+	fn := p.tok
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if fn.arg == "" {
+		return nil, p.lex.errorf(fn.pos, "%s requires an argument", fn.value)
+	}
+
+	switch fn.value {
+	case labelPredicate:
+		return selectorNode{selector: document.NewSelector().ByLabel(fn.arg), text: "label(" + fn.arg + ")"}, nil
+	case annotationPredicate:
+		return selectorNode{
+			selector: document.NewSelector().ByAnnotation(fn.arg),
+			text:     "annotation(" + fn.arg + ")",
+		}, nil
+	default:
+		if p.tok.kind != tokenEqual && p.tok.kind != tokenNotEqual {
+			return fieldNode{path: fn.arg}, nil
+		}
+		negate := p.tok.kind == tokenNotEqual
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return negateIf(negate, fieldNode{path: fn.arg, value: value, compare: true}), nil
+	}
+}
+
+func (p *parser) parseAttribute() (node, error) {
+	attr := p.tok
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEqual && p.tok.kind != tokenNotEqual {
+		return nil, p.lex.errorf(p.tok.pos, "expected \"=\" or \"!=\" after %q", attr.value)
+	}
+	negate := p.tok.kind == tokenNotEqual
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	var n node
+	selector := document.NewSelector()
+	text := attr.value + "=" + value
+	switch {
+	case strings.EqualFold(attr.value, kindPredicate):
+		n = selectorNode{selector: selector.ByKind(value), text: text}
+	case strings.EqualFold(attr.value, namePredicate):
+		n = selectorNode{selector: selector.ByName(value), text: text}
+	case strings.EqualFold(attr.value, namespacePredicate):
+		n = selectorNode{selector: selector.ByNamespace(value), text: text}
+	case strings.EqualFold(attr.value, apiVersionPredicate):
+		// selector treats empty group as any group, so core API
+		// versions like v1 are compared literally instead
+		n = fieldNode{path: apiVersionPredicate, value: value, compare: true}
+	default:
+		return nil, p.lex.errorf(attr.pos, "unknown attribute %q", attr.value)
+	}
+	return negateIf(negate, n), nil
+}
+
+// parseValue expects current token to be comparison and reads the value after it
+func (p *parser) parseValue() (string, error) {
+	if err := p.advance(); err != nil {
+		return "", err
+	}
+	if p.tok.kind != tokenWord && p.tok.kind != tokenString {
+		return "", p.lex.errorf(p.tok.pos, "expected value")
+	}
+	value := p.tok.value
+	return value, p.advance()
+}
+
+func negateIf(negate bool, n node) node {
+	if negate {
+		return notNode{operand: n}
+	}
+	return n
+}
@@ -0,0 +1,30 @@
+package lock
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidLock returned if the lock file can't be parsed or is not a lock file
+type ErrInvalidLock struct {
+	Path string
+	Err  error
+}
+
+func (e ErrInvalidLock) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("Invalid lock file %s: %v", e.Path, e.Err)
+	}
+	return fmt.Sprintf("Invalid lock file %s: expected apiVersion %s and kind %s", e.Path, APIVersion, Kind)
+}
+
+// ErrLockMismatch returned if working trees of repositories don't match the lock
+type ErrLockMismatch struct {
+	Path         string
+	Repositories []string
+}
+
+func (e ErrLockMismatch) Error() string {
+	return fmt.Sprintf("Repositories %s don't match the lock file %s",
+		strings.Join(e.Repositories, ", "), e.Path)
+}
@@ -0,0 +1,144 @@
+package lock
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+const (
+	// FileName is the name of the lock file in the target path of the
+	// manifest if the manifest doesn't set the lock path
+	FileName = "airship-manifest.lock"
+
+	// APIVersion and Kind identify lock files
+	APIVersion = "airshipit.org/v1alpha1"
+	Kind       = "ManifestLock"
+)
+
+// Lock pins every repository of the manifest to the commit resolved by the
+// last pull so that everyone pulling with the lock renders the same content
+type Lock struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Repositories maps names of manifest repositories to their entries
+	Repositories map[string]*Entry `json:"repositories"`
+}
+
+// Entry is the commit the repository is locked at
+type Entry struct {
+	// URL is the url of the repository the commit was resolved from, the
+	// entry only applies to the repository with the same url
+	URL string `json:"url"`
+	// Checkout is the branch, tag, remote ref or commit the commit was
+	// resolved from, the entry only applies to the repository checking out
+	// the same one
+	Checkout string `json:"checkout,omitempty"`
+	// Commit is the hash of the resolved commit
+	Commit string `json:"commit"`
+}
+
+// New returns an empty lock
+func New() *Lock {
+	return &Lock{
+		APIVersion:   APIVersion,
+		Kind:         Kind,
+		Repositories: make(map[string]*Entry),
+	}
+}
+
+// Path returns the path of the lock file of the manifest, the lock path of
+// the manifest or FileName in its target path if it is not set
+func Path(manifest *config.Manifest) string {
+	switch {
+	case manifest.LockPath == "":
+		return filepath.Join(manifest.TargetPath, FileName)
+	case filepath.IsAbs(manifest.LockPath):
+		return manifest.LockPath
+	default:
+		return filepath.Join(manifest.TargetPath, manifest.LockPath)
+	}
+}
+
+// Read reads the lock file, an empty lock is returned if it doesn't exist
+func Read(path string) (*Lock, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, path)
+}
+
+// Parse parses the lock read from the source, e.g. the file name
+func Parse(data []byte, source string) (*Lock, error) {
+	l := &Lock{}
+	if err := yaml.Unmarshal(data, l); err != nil {
+		return nil, ErrInvalidLock{Path: source, Err: err}
+	}
+	if l.APIVersion != APIVersion || l.Kind != Kind {
+		return nil, ErrInvalidLock{Path: source}
+	}
+	if l.Repositories == nil {
+		l.Repositories = make(map[string]*Entry)
+	}
+	return l, nil
+}
+
+// Marshal returns the lock in YAML
+func (l *Lock) Marshal() ([]byte, error) {
+	return yaml.Marshal(l)
+}
+
+// Write writes the lock file
+func (l *Lock) Write(path string) error {
+	data, err := l.Marshal()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// Commit returns the commit the repository is locked at, entries of
+// repositories whose url or checkout options have changed are ignored
+func (l *Lock) Commit(name string, repository *config.Repository) (string, bool) {
+	entry, found := l.Repositories[name]
+	if !found || entry.URL != repository.URL() || entry.Checkout != checkout(repository) || entry.Commit == "" {
+		return "", false
+	}
+	return entry.Commit, true
+}
+
+// Set locks the repository at the commit
+func (l *Lock) Set(name string, repository *config.Repository, commit string) {
+	l.Repositories[name] = &Entry{URL: repository.URL(), Checkout: checkout(repository), Commit: commit}
+}
+
+// checkout returns what the repository checks out, e.g. branch/master, the
+// default branch of the remote is checked out if nothing is set
+func checkout(repository *config.Repository) string {
+	options := repository.CheckoutOptions
+	switch {
+	case options == nil:
+		return ""
+	case options.CommitHash != "":
+		return "commit/" + options.CommitHash
+	case options.Branch != "":
+		return "branch/" + options.Branch
+	case options.Tag != "":
+		return "tag/" + options.Tag
+	case options.RemoteRef != "":
+		return "ref/" + options.RemoteRef
+	default:
+		return ""
+	}
+}
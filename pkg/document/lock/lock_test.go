@@ -0,0 +1,107 @@
+package lock_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/lock"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestReadWrite(t *testing.T) {
+	tmpDir, cleanup := testutil.TempDir(t, "airshipctlLockTest-")
+	defer cleanup(t)
+	path := filepath.Join(tmpDir, lock.FileName)
+
+	l, err := lock.Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, lock.New(), l)
+
+	repository := &config.Repository{URLString: "https://opendev.org/airship/treasuremap"}
+	l.Set("primary", repository, "6ecf0ef2c2dffb796033e5a02219af86ec6584e5")
+	require.NoError(t, l.Write(path))
+
+	read, err := lock.Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, l, read)
+}
+
+func TestReadInvalid(t *testing.T) {
+	tmpDir, cleanup := testutil.TempDir(t, "airshipctlLockTest-")
+	defer cleanup(t)
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "not-yaml",
+			data: "repositories: [",
+		},
+		{
+			name: "wrong-kind",
+			data: "apiVersion: v1\nkind: ConfigMap\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, tt.name)
+			require.NoError(t, ioutil.WriteFile(path, []byte(tt.data), 0600))
+			_, err := lock.Read(path)
+			assert.IsType(t, lock.ErrInvalidLock{}, err)
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	manifest := &config.Manifest{TargetPath: "/tmp/airship"}
+	assert.Equal(t, filepath.Join("/tmp/airship", lock.FileName), lock.Path(manifest))
+
+	manifest.LockPath = "locks/airship-manifest.lock"
+	assert.Equal(t, "/tmp/airship/locks/airship-manifest.lock", lock.Path(manifest))
+
+	manifest.LockPath = "/etc/airship/airship-manifest.lock"
+	assert.Equal(t, "/etc/airship/airship-manifest.lock", lock.Path(manifest))
+}
+
+func TestCommit(t *testing.T) {
+	repository := &config.Repository{
+		URLString:       "https://opendev.org/airship/treasuremap",
+		CheckoutOptions: &config.RepoCheckout{Branch: "master"},
+	}
+	l := lock.New()
+	l.Set("primary", repository, "6ecf0ef2c2dffb796033e5a02219af86ec6584e5")
+
+	commit, locked := l.Commit("primary", repository)
+	assert.True(t, locked)
+	assert.Equal(t, "6ecf0ef2c2dffb796033e5a02219af86ec6584e5", commit)
+
+	_, locked = l.Commit("extra", repository)
+	assert.False(t, locked)
+
+	moved := &config.Repository{
+		URLString:       "https://github.com/airshipit/treasuremap",
+		CheckoutOptions: repository.CheckoutOptions,
+	}
+	_, locked = l.Commit("primary", moved)
+	assert.False(t, locked)
+
+	// entries of repositories checking out another branch, tag or ref are
+	// ignored
+	for _, checkout := range []*config.RepoCheckout{
+		nil,
+		{Branch: "stable"},
+		{Tag: "master"},
+		{RemoteRef: "refs/changes/04/691202/5"},
+	} {
+		changed := &config.Repository{URLString: repository.URLString, CheckoutOptions: checkout}
+		_, locked = l.Commit("primary", changed)
+		assert.False(t, locked, checkout)
+	}
+}
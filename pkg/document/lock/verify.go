@@ -0,0 +1,88 @@
+package lock
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/repo"
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// Settings for verifying working trees of the manifest against its lock
+type Settings struct {
+	*environment.AirshipCTLSettings
+}
+
+// Verify checks that every repository of the current manifest is checked out
+// at its locked commit without local changes, the state of each repository
+// is written to out
+func (s *Settings) Verify(out io.Writer) error {
+	manifest, err := s.Config().CurrentContextManifest()
+	if err != nil {
+		return err
+	}
+	path := Path(manifest)
+	l, err := Read(path)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(manifest.Repositories))
+	for name := range manifest.Repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var mismatched []string
+	for _, name := range names {
+		state, err := verifyRepository(manifest.TargetPath, manifest.Repositories[name], l, name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s: %s\n", name, state)
+		if state != stateMatches {
+			mismatched = append(mismatched, name)
+		}
+	}
+	if len(mismatched) > 0 {
+		return ErrLockMismatch{Path: path, Repositories: mismatched}
+	}
+	return nil
+}
+
+const stateMatches = "matches the lock"
+
+// verifyRepository returns the state of the working tree of the repository
+// compared to the lock
+func verifyRepository(basePath string, repoConfig *config.Repository, l *Lock, name string) (string, error) {
+	commit, locked := l.Commit(name, repoConfig)
+	if !locked {
+		return "not locked", nil
+	}
+	repository, err := repo.NewRepository(basePath, repoConfig)
+	if err != nil {
+		return "", err
+	}
+	if err = repository.Open(); err != nil {
+		return "not pulled", nil
+	}
+	defer repository.Driver.Close()
+
+	head, err := repository.Driver.Head()
+	if err != nil {
+		return "", err
+	}
+	if head.Hash().String() != commit {
+		return fmt.Sprintf("checked out at %s, locked at %s", head.Hash(), commit), nil
+	}
+	clean, err := repository.IsClean()
+	if err != nil {
+		return "", err
+	}
+	if !clean {
+		return "working tree has local changes", nil
+	}
+	return stateMatches, nil
+}
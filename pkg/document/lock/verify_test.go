@@ -0,0 +1,113 @@
+package lock_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	fixtures "gopkg.in/src-d/go-git-fixtures.v3"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/lock"
+	"opendev.org/airship/airshipctl/pkg/document/repo"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/util"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestVerify(t *testing.T) {
+	require.NoError(t, fixtures.Init())
+	defer testutil.CleanUpGitFixtures(t)
+	fx := fixtures.Basic().One()
+
+	tests := []struct {
+		name        string
+		lockedAt    string
+		pull        bool
+		modify      bool
+		expectedOut string
+		expectedErr bool
+	}{
+		{
+			name:        "matches",
+			lockedAt:    fx.Head.String(),
+			pull:        true,
+			expectedOut: "primary: matches the lock\n",
+		},
+		{
+			name:     "other-commit",
+			lockedAt: "e8d3ffab552895c19b9fcf7aa264d277cde33881",
+			pull:     true,
+			expectedOut: "primary: checked out at " + fx.Head.String() +
+				", locked at e8d3ffab552895c19b9fcf7aa264d277cde33881\n",
+			expectedErr: true,
+		},
+		{
+			name:        "local-changes",
+			lockedAt:    fx.Head.String(),
+			pull:        true,
+			modify:      true,
+			expectedOut: "primary: working tree has local changes\n",
+			expectedErr: true,
+		},
+		{
+			name:        "not-pulled",
+			lockedAt:    fx.Head.String(),
+			expectedOut: "primary: not pulled\n",
+			expectedErr: true,
+		},
+		{
+			name:        "not-locked",
+			pull:        true,
+			expectedOut: "primary: not locked\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, cleanup := testutil.TempDir(t, "airshipctlLockTest-")
+			defer cleanup(t)
+
+			conf := testutil.DummyConfig()
+			mfst := conf.Manifests["dummy_manifest"]
+			mfst.TargetPath = tmpDir
+			primary := &config.Repository{
+				URLString:       fx.DotGit().Root(),
+				CheckoutOptions: &config.RepoCheckout{Branch: "master"},
+			}
+			mfst.Repositories = map[string]*config.Repository{"primary": primary}
+
+			if tt.pull {
+				repository, err := repo.NewRepository(tmpDir, primary)
+				require.NoError(t, err)
+				require.NoError(t, repository.Download(true))
+				repository.Driver.Close()
+			}
+			if tt.modify {
+				path := filepath.Join(tmpDir, util.GitDirNameFromURL(primary.URL()), "CHANGELOG")
+				require.NoError(t, ioutil.WriteFile(path, []byte("modified"), 0600))
+			}
+			l := lock.New()
+			if tt.lockedAt != "" {
+				l.Set("primary", primary, tt.lockedAt)
+			}
+			require.NoError(t, l.Write(lock.Path(mfst)))
+
+			settings := &lock.Settings{AirshipCTLSettings: new(environment.AirshipCTLSettings)}
+			settings.SetConfig(conf)
+			out := &bytes.Buffer{}
+			err := settings.Verify(out)
+			if tt.expectedErr {
+				assert.IsType(t, lock.ErrLockMismatch{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedOut, out.String())
+		})
+	}
+}
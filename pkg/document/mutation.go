@@ -0,0 +1,237 @@
+package document
+
+import (
+	"bytes"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// NewDocumentFromBytes returns the document of a single YAML or JSON document
+func NewDocumentFromBytes(data []byte) (Document, error) {
+	res, err := newResource(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewDocument(res)
+}
+
+// Append adds a copy of the document to the end of the bundle. Documents are
+// identified by their kind, namespace and name, the bundle may not hold two
+// documents with the same id
+func (b *BundleFactory) Append(doc Document) error {
+	res, err := documentResource(doc)
+	if err != nil {
+		return err
+	}
+	if b.findByID(res) != nil {
+		return ErrDocumentExists{ID: res.CurId().String()}
+	}
+	if err = b.ResMap.Append(res); err != nil {
+		return err
+	}
+	b.setOrigin(res, doc.Origin())
+	return nil
+}
+
+// Replace replaces the document of the bundle having the same id with a copy
+// of the document, its position in the bundle is kept
+func (b *BundleFactory) Replace(doc Document) error {
+	res, err := documentResource(doc)
+	if err != nil {
+		return err
+	}
+	existing := b.findByID(res)
+	if existing == nil {
+		return ErrDocNotFound{Selector: idSelector(res)}
+	}
+	b.setOrigin(res, doc.Origin())
+	return b.replaceResources(map[*resource.Resource]*resource.Resource{existing: res})
+}
+
+// Delete removes documents matching the selector from the bundle
+func (b *BundleFactory) Delete(selector Selector) error {
+	resources, err := b.ResMap.Select(selector.Selector)
+	if err != nil {
+		return err
+	}
+	deleted := make(map[*resource.Resource]*resource.Resource, len(resources))
+	for _, res := range resources {
+		deleted[res] = nil
+	}
+	return b.replaceResources(deleted)
+}
+
+// Patch applies the patch to every document matching the selector. The
+// patch is either a strategic merge patch, which is a partial document, or a
+// JSON 6902 patch, which is a list of operations, in YAML or JSON form.
+// Documents of kinds unknown to kubectl are patched with JSON merge patches
+// instead of strategic merge ones. No document is changed if the patch fails
+// on any of them or if patched documents end up with the same id
+func (b *BundleFactory) Patch(selector Selector, patch []byte) error {
+	p, err := newDocumentPatch(patch)
+	if err != nil {
+		return err
+	}
+	resources, err := b.ResMap.Select(selector.Selector)
+	if err != nil {
+		return err
+	}
+
+	patched := make(map[*resource.Resource]*resource.Resource, len(resources))
+	for _, res := range resources {
+		data, err := res.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if data, err = p.apply(res, data); err != nil {
+			return ErrPatchFailed{Document: res.CurId().String(), Err: err}
+		}
+		newRes, err := newResource(data)
+		if err != nil {
+			return ErrPatchFailed{Document: res.CurId().String(), Err: err}
+		}
+		b.setOrigin(newRes, b.origins[res])
+		patched[res] = newRes
+	}
+	return b.replaceResources(patched)
+}
+
+// replaceResources rebuilds the resource map replacing resources by their
+// replacements in place, resources replaced by nil are removed. The bundle
+// is left as is if any id is taken twice
+func (b *BundleFactory) replaceResources(replacements map[*resource.Resource]*resource.Resource) error {
+	m, err := b.replacedResMap(replacements)
+	for old, replacement := range replacements {
+		if err != nil {
+			delete(b.origins, replacement)
+		} else {
+			delete(b.origins, old)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	b.ResMap = m
+	return nil
+}
+
+func (b *BundleFactory) replacedResMap(replacements map[*resource.Resource]*resource.Resource) (resmap.ResMap, error) {
+	m := resmap.New()
+	seen := make(map[string]bool, b.ResMap.Size())
+	for _, res := range b.ResMap.Resources() {
+		if replacement, found := replacements[res]; found {
+			if replacement == nil {
+				continue
+			}
+			res = replacement
+		}
+		id := res.CurId().String()
+		if seen[id] {
+			return nil, ErrDocumentExists{ID: id}
+		}
+		seen[id] = true
+		if err := m.Append(res); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// findByID returns the resource of the bundle with the same kind, namespace
+// and name as res
+func (b *BundleFactory) findByID(res *resource.Resource) *resource.Resource {
+	for _, r := range b.ResMap.Resources() {
+		if r.GetGvk() == res.GetGvk() && r.GetNamespace() == res.GetNamespace() && r.GetName() == res.GetName() {
+			return r
+		}
+	}
+	return nil
+}
+
+func (b *BundleFactory) setOrigin(res *resource.Resource, origin *Origin) {
+	if origin == nil {
+		return
+	}
+	if b.origins == nil {
+		b.origins = make(map[*resource.Resource]*Origin)
+	}
+	b.origins[res] = origin
+}
+
+// idSelector selects the document with the id of the resource
+func idSelector(res *resource.Resource) Selector {
+	gvk := res.GetGvk()
+	return NewSelector().
+		ByGvk(gvk.Group, gvk.Version, gvk.Kind).
+		ByNamespace(res.GetNamespace()).
+		ByName(res.GetName())
+}
+
+// documentResource returns a copy of the document as kustomize resource
+func documentResource(doc Document) (*resource.Resource, error) {
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return newResource(data)
+}
+
+func newResource(data []byte) (*resource.Resource, error) {
+	m, err := newResMapFactory().NewResMapFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	if m.Size() != 1 {
+		return nil, ErrUnexpectedDocumentCount{Count: m.Size()}
+	}
+	return m.Resources()[0], nil
+}
+
+// documentPatch is either a strategic merge or a JSON 6902 patch
+type documentPatch struct {
+	merge []byte
+	ops   jsonpatch.Patch
+}
+
+func newDocumentPatch(patch []byte) (*documentPatch, error) {
+	data, err := yaml.YAMLToJSON(patch)
+	if err != nil {
+		return nil, ErrInvalidPatch{Err: err}
+	}
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(data, []byte("[")):
+		ops, err := jsonpatch.DecodePatch(data)
+		if err != nil {
+			return nil, ErrInvalidPatch{Err: err}
+		}
+		return &documentPatch{ops: ops}, nil
+	case bytes.HasPrefix(data, []byte("{")):
+		return &documentPatch{merge: data}, nil
+	default:
+		return nil, ErrInvalidPatch{}
+	}
+}
+
+// apply patches JSON data of the resource
+func (p *documentPatch) apply(res *resource.Resource, data []byte) ([]byte, error) {
+	if p.ops != nil {
+		return p.ops.Apply(data)
+	}
+	gvk := res.GetGvk()
+	versioned, err := scheme.Scheme.New(schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind})
+	if runtime.IsNotRegisteredError(err) {
+		return jsonpatch.MergePatch(data, p.merge)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strategicpatch.StrategicMergePatch(data, p.merge, versioned)
+}
@@ -0,0 +1,210 @@
+package document_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const siteConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: site-config
+  namespace: argo
+data:
+  site: test-site
+`
+
+func TestBundleAppend(t *testing.T) {
+	bundle := testutil.NewTestBundle(t, "testdata/common")
+	docs, err := bundle.GetAllDocuments()
+	require.NoError(t, err)
+
+	doc, err := document.NewDocumentFromBytes([]byte(siteConfigMap))
+	require.NoError(t, err)
+	require.NoError(t, bundle.Append(doc))
+
+	appended, err := bundle.GetAllDocuments()
+	require.NoError(t, err)
+	require.Len(t, appended, len(docs)+1)
+	assert.Equal(t, "site-config", appended[len(docs)].GetName())
+
+	// the bundle keeps a copy of the document
+	doc.Label("changed", "true")
+	appendedDoc, err := bundle.GetByName("site-config")
+	require.NoError(t, err)
+	assert.Empty(t, appendedDoc.GetLabels())
+
+	err = bundle.Append(doc)
+	assert.IsType(t, document.ErrDocumentExists{}, err)
+}
+
+func TestBundleReplace(t *testing.T) {
+	bundle := testutil.NewTestBundle(t, "testdata/common")
+	docs, err := bundle.GetAllDocuments()
+	require.NoError(t, err)
+
+	doc, err := document.NewDocumentFromBytes([]byte(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: argo-ui-binding
+  labels:
+    replaced: "true"
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: argo-ui-cluster-role
+`))
+	require.NoError(t, err)
+	require.NoError(t, bundle.Replace(doc))
+
+	replaced, err := bundle.GetAllDocuments()
+	require.NoError(t, err)
+	require.Len(t, replaced, len(docs))
+	for i := range docs {
+		assert.Equal(t, docs[i].GetName(), replaced[i].GetName())
+	}
+	doc, err = bundle.GetByName("argo-ui-binding")
+	require.NoError(t, err)
+	assert.Equal(t, "true", doc.GetLabels()["replaced"])
+
+	missing, err := document.NewDocumentFromBytes([]byte(siteConfigMap))
+	require.NoError(t, err)
+	err = bundle.Replace(missing)
+	assert.IsType(t, document.ErrDocNotFound{}, err)
+}
+
+func TestBundleDelete(t *testing.T) {
+	bundle := testutil.NewTestBundle(t, "testdata/common")
+
+	require.NoError(t, bundle.Delete(document.NewSelector().ByKind("ClusterRole")))
+	docs, err := bundle.GetByGvk("rbac.authorization.k8s.io", "v1", "ClusterRole")
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+	docs, err = bundle.GetByGvk("rbac.authorization.k8s.io", "v1", "ClusterRoleBinding")
+	require.NoError(t, err)
+	assert.Len(t, docs, 2)
+}
+
+func TestBundlePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector document.Selector
+		patch    string
+		check    func(*testing.T, document.Bundle)
+	}{
+		{
+			name:     "strategic-merge",
+			selector: document.NewSelector().ByKind("Deployment").ByName("argo-ui"),
+			patch: `spec:
+  template:
+    spec:
+      containers:
+      - name: argo-ui
+        image: argoproj/argoui:v2.4.0
+`,
+			check: func(t *testing.T, bundle document.Bundle) {
+				doc, err := bundle.SelectOne(document.NewSelector().ByKind("Deployment").ByName("argo-ui"))
+				require.NoError(t, err)
+				containers, err := doc.GetSlice("spec.template.spec.containers")
+				require.NoError(t, err)
+				require.Len(t, containers, 1)
+				container := containers[0].(map[string]interface{})
+				assert.Equal(t, "argoproj/argoui:v2.4.0", container["image"])
+				assert.Len(t, container["env"], 4)
+			},
+		},
+		{
+			name:     "merge-of-custom-resource",
+			selector: document.NewSelector().ByKind("BareMetalHost"),
+			patch:    `{"spec": {"online": false}}`,
+			check: func(t *testing.T, bundle document.Bundle) {
+				docs, err := bundle.GetByGvk("metal3.io", "v1alpha1", "BareMetalHost")
+				require.NoError(t, err)
+				require.NotEmpty(t, docs)
+				for _, doc := range docs {
+					online, err := doc.GetBool("spec.online")
+					require.NoError(t, err)
+					assert.False(t, online)
+				}
+			},
+		},
+		{
+			name:     "json6902",
+			selector: document.NewSelector().ByKind("BareMetalHost").ByName("master-0"),
+			patch: `- op: replace
+  path: /spec/bmc/address
+  value: redfish+https://10.23.25.1/redfish/v1/Systems/master-0
+`,
+			check: func(t *testing.T, bundle document.Bundle) {
+				doc, err := bundle.GetByName("master-0")
+				require.NoError(t, err)
+				address, err := doc.GetString("spec.bmc.address")
+				require.NoError(t, err)
+				assert.Equal(t, "redfish+https://10.23.25.1/redfish/v1/Systems/master-0", address)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := testutil.NewTestBundle(t, "testdata/common")
+			require.NoError(t, bundle.Patch(tt.selector, []byte(tt.patch)))
+			tt.check(t, bundle)
+		})
+	}
+}
+
+func TestBundlePatchErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		selector    document.Selector
+		patch       string
+		expectedErr string
+	}{
+		{
+			name:        "not-a-patch",
+			selector:    document.NewSelector().ByKind("Deployment"),
+			patch:       "just a string",
+			expectedErr: "Patch must be either a partial document or a list of JSON 6902 operations",
+		},
+		{
+			name:        "failed-operation",
+			selector:    document.NewSelector().ByKind("Deployment").ByName("argo-ui"),
+			patch:       `[{"op": "remove", "path": "/spec/missing"}]`,
+			expectedErr: "Failed to patch document",
+		},
+		{
+			name:        "duplicate-id",
+			selector:    document.NewSelector().ByKind("ServiceAccount"),
+			patch:       "metadata:\n  name: same\n",
+			expectedErr: "already exists in the bundle",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := testutil.NewTestBundle(t, "testdata/common")
+			docs, err := bundle.GetAllDocuments()
+			require.NoError(t, err)
+
+			err = bundle.Patch(tt.selector, []byte(tt.patch))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+
+			// the bundle is left as is
+			unchanged, err := bundle.GetAllDocuments()
+			require.NoError(t, err)
+			require.Len(t, unchanged, len(docs))
+			for i := range docs {
+				assert.Equal(t, docs[i].GetName(), unchanged[i].GetName())
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package document
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// OriginAnnotation is set to the origin of documents by document render
+	// --show-origin
+	OriginAnnotation = "airshipit.org/origin"
+
+	// originIDAnnotation traces resources back to files they are loaded from
+	// while the bundle is built, it never leaves NewBundle
+	originIDAnnotation = "origin.airshipit.org/id"
+	inlinePatch        = "<inline>"
+)
+
+// kustomizationFileNames are the names kustomize looks for in kustomization roots
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// Origin tells where the document comes from
+type Origin struct {
+	// Path is the file the document is loaded from
+	Path string `json:"path"`
+	// Kustomization is the kustomization root listing the file in resources
+	Kustomization string `json:"kustomization"`
+	// Patches are patches applied to the document by the kustomization and
+	// kustomizations including it, innermost first
+	Patches []string `json:"patches,omitempty"`
+	// Transformers are configurations of transformers run over the
+	// kustomization and kustomizations including it, innermost first
+	Transformers []string `json:"transformers,omitempty"`
+}
+
+// String returns the origin in YAML
+func (o *Origin) String() string {
+	data, err := yaml.Marshal(o)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+// kustomizationFile holds the fields of kustomization files that tell where
+// documents come from
+type kustomizationFile struct {
+	Resources             []string `json:"resources,omitempty"`
+	PatchesStrategicMerge []string `json:"patchesStrategicMerge,omitempty"`
+	PatchesJSON6902       []struct {
+		Target struct {
+			Kind      string `json:"kind,omitempty"`
+			Name      string `json:"name,omitempty"`
+			Namespace string `json:"namespace,omitempty"`
+		} `json:"target,omitempty"`
+		Path string `json:"path,omitempty"`
+	} `json:"patchesJson6902,omitempty"`
+	Transformers []string `json:"transformers,omitempty"`
+}
+
+// kustomizationNode is a kustomization root loaded during the build
+type kustomizationNode struct {
+	root      string
+	parent    *kustomizationNode
+	resources map[string]bool
+	patches   []originPatch
+	// transformers are paths of transformer configurations
+	transformers []string
+}
+
+// originPatch is a patch along with the document it targets
+type originPatch struct {
+	path      string
+	kind      string
+	name      string
+	namespace string
+}
+
+// matches tells if the patch targets the resource, kustomize matches patches
+// either by the original or the current name of resources
+func (p originPatch) matches(res *resource.Resource, doc *loadedDocument) bool {
+	if p.kind != res.GetKind() || (p.name != doc.name && p.name != res.GetName()) {
+		return false
+	}
+	return p.namespace == "" || p.namespace == doc.namespace || p.namespace == res.GetNamespace()
+}
+
+// loadedDocument is a document loaded from a resources file
+type loadedDocument struct {
+	path      string
+	node      *kustomizationNode
+	name      string
+	namespace string
+}
+
+// origin returns the origin of the resource loaded as the document
+func (d *loadedDocument) origin(res *resource.Resource) *Origin {
+	origin := &Origin{Path: d.path, Kustomization: d.node.root}
+	for node := d.node; node != nil; node = node.parent {
+		for _, patch := range node.patches {
+			if patch.matches(res, d) {
+				origin.Patches = append(origin.Patches, patch.path)
+			}
+		}
+		origin.Transformers = append(origin.Transformers, node.transformers...)
+	}
+	return origin
+}
+
+// originTracker follows kustomize loading files and marks resources with
+// documents they are loaded from. Tracking is best effort, it never fails
+// the build
+type originTracker struct {
+	ifc.KunstructuredFactory
+	// pending is the resources file the loader has just read, the next
+	// SliceFromBytes call decodes it
+	pending   *loadedDocument
+	documents []*loadedDocument
+}
+
+func newOriginTracker(kf ifc.KunstructuredFactory) *originTracker {
+	return &originTracker{KunstructuredFactory: kf}
+}
+
+// SliceFromBytes decodes documents and marks them if they come from a
+// resources file
+func (t *originTracker) SliceFromBytes(data []byte) ([]ifc.Kunstructured, error) {
+	objs, err := t.KunstructuredFactory.SliceFromBytes(data)
+	file := t.pending
+	t.pending = nil
+	if err != nil || file == nil {
+		return objs, err
+	}
+	for _, obj := range objs {
+		namespace, _ := obj.GetString("metadata.namespace")
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[originIDAnnotation] = strconv.Itoa(len(t.documents))
+		obj.SetAnnotations(annotations)
+		t.documents = append(t.documents, &loadedDocument{
+			path:      file.path,
+			node:      file.node,
+			name:      obj.GetName(),
+			namespace: namespace,
+		})
+	}
+	return objs, nil
+}
+
+// loader returns the loader tracking files loaded by ldr
+func (t *originTracker) loader(ldr ifc.Loader, parent *kustomizationNode) ifc.Loader {
+	node := &kustomizationNode{root: ldr.Root(), parent: parent, resources: make(map[string]bool)}
+	for _, name := range kustomizationFileNames {
+		data, err := ldr.Load(name)
+		if err != nil {
+			continue
+		}
+		t.readKustomization(ldr, node, data)
+		break
+	}
+	return &originLoader{Loader: ldr, tracker: t, node: node}
+}
+
+// readKustomization fills the node with resources, patches and transformers
+// of the kustomization file
+func (t *originTracker) readKustomization(ldr ifc.Loader, node *kustomizationNode, data []byte) {
+	k := &kustomizationFile{}
+	if err := yaml.Unmarshal(data, k); err != nil {
+		return
+	}
+	for _, path := range k.Resources {
+		node.resources[filepath.Clean(path)] = true
+	}
+	for _, path := range k.Transformers {
+		node.transformers = append(node.transformers, filepath.Join(node.root, path))
+	}
+	for _, p := range k.PatchesJSON6902 {
+		node.patches = append(node.patches, originPatch{
+			path:      filepath.Join(node.root, p.Path),
+			kind:      p.Target.Kind,
+			name:      p.Target.Name,
+			namespace: p.Target.Namespace,
+		})
+	}
+	for _, entry := range k.PatchesStrategicMerge {
+		// entries are either files or patches themselves
+		path := inlinePatch
+		patch, err := ldr.Load(entry)
+		if err == nil {
+			path = filepath.Join(node.root, entry)
+		} else {
+			patch = []byte(entry)
+		}
+		objs, err := t.KunstructuredFactory.SliceFromBytes(patch)
+		if err != nil {
+			continue
+		}
+		for _, obj := range objs {
+			namespace, _ := obj.GetString("metadata.namespace")
+			node.patches = append(node.patches, originPatch{
+				path:      path,
+				kind:      obj.GetKind(),
+				name:      obj.GetName(),
+				namespace: namespace,
+			})
+		}
+	}
+}
+
+// resolve returns origins of resources marked while the resource map was
+// built and removes the marks
+func (t *originTracker) resolve(m resmap.ResMap) map[*resource.Resource]*Origin {
+	origins := make(map[*resource.Resource]*Origin)
+	for _, res := range m.Resources() {
+		annotations := res.GetAnnotations()
+		id, found := annotations[originIDAnnotation]
+		if !found {
+			continue
+		}
+		delete(annotations, originIDAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		res.SetAnnotations(annotations)
+
+		i, err := strconv.Atoi(id)
+		if err != nil || i < 0 || i >= len(t.documents) {
+			continue
+		}
+		origins[res] = t.documents[i].origin(res)
+	}
+	return origins
+}
+
+// originLoader tells the tracker which resources files it loads
+type originLoader struct {
+	ifc.Loader
+	tracker *originTracker
+	node    *kustomizationNode
+}
+
+// New returns the tracking loader of the kustomization root
+func (l *originLoader) New(newRoot string) (ifc.Loader, error) {
+	ldr, err := l.Loader.New(newRoot)
+	if err != nil {
+		return nil, err
+	}
+	return l.tracker.loader(ldr, l.node), nil
+}
+
+// Load reads the file and tells the tracker if it is a resources file
+func (l *originLoader) Load(location string) ([]byte, error) {
+	data, err := l.Loader.Load(location)
+	l.tracker.pending = nil
+	if err == nil && l.node.resources[filepath.Clean(location)] {
+		l.tracker.pending = &loadedDocument{path: filepath.Join(l.Root(), location), node: l.node}
+	}
+	return data, err
+}
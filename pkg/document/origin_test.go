@@ -0,0 +1,74 @@
+package document_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+func TestDocumentOrigin(t *testing.T) {
+	base, err := filepath.Abs("testdata/origin/base")
+	require.NoError(t, err)
+	overlay, err := filepath.Abs("testdata/origin/overlay")
+	require.NoError(t, err)
+
+	bundle, err := document.NewBundle(document.NewDocumentFs(), overlay, "")
+	require.NoError(t, err)
+
+	app, err := bundle.GetByName("app")
+	require.NoError(t, err)
+	assert.Equal(t, &document.Origin{
+		Path:          filepath.Join(base, "deployment.yaml"),
+		Kustomization: base,
+		Patches:       []string{filepath.Join(overlay, "replicas.yaml")},
+		Transformers:  []string{filepath.Join(overlay, "replacement.yaml")},
+	}, app.Origin())
+	assert.Empty(t, app.GetAnnotations())
+
+	settings, err := bundle.GetByName("settings")
+	require.NoError(t, err)
+	assert.Equal(t, &document.Origin{
+		Path:          filepath.Join(overlay, "configmap.yaml"),
+		Kustomization: overlay,
+		Transformers:  []string{filepath.Join(overlay, "replacement.yaml")},
+	}, settings.Origin())
+	assert.Equal(t, map[string]string{"note": "kept"}, settings.GetAnnotations())
+
+	docs, err := bundle.GetAllDocuments()
+	require.NoError(t, err)
+	for _, doc := range docs {
+		if strings.HasPrefix(doc.GetName(), "generated-") {
+			assert.Nil(t, doc.Origin())
+		}
+	}
+
+	// origins are kept by bundles of selected documents
+	selected, err := bundle.SelectBundle(document.NewSelector().ByKind("Deployment"))
+	require.NoError(t, err)
+	app, err = selected.GetByName("app")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(base, "deployment.yaml"), app.Origin().Path)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, bundle.Write(out))
+	assert.NotContains(t, out.String(), "origin.airshipit.org")
+}
+
+func TestOriginString(t *testing.T) {
+	origin := &document.Origin{
+		Path:          "/site/base/deployment.yaml",
+		Kustomization: "/site/base",
+		Patches:       []string{"/site/overlay/replicas.yaml"},
+	}
+	assert.Equal(t, `kustomization: /site/base
+path: /site/base/deployment.yaml
+patches:
+- /site/overlay/replicas.yaml
+`, origin.String())
+}
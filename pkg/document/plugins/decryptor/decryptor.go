@@ -0,0 +1,69 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+// Kind of the decryptor configuration document
+const Kind = "SecretDecryptor"
+
+// Decryptor decrypts values of Secret documents encrypted with the site key,
+// see secret.SiteKey for the ways to supply the key
+type Decryptor struct{}
+
+// New returns decryptor plugin instance
+func New() resmap.TransformerPlugin {
+	return &Decryptor{}
+}
+
+// Config does nothing since decryptor has no settings
+func (d *Decryptor) Config(_ ifc.Loader, _ *resmap.Factory, _ []byte) error {
+	return nil
+}
+
+// Transform decrypts encrypted values of Secret documents, the site key is
+// only required if the resources have encrypted values
+func (d *Decryptor) Transform(m resmap.ResMap) error {
+	var c *secret.Cipher
+	for _, res := range m.Resources() {
+		obj := res.Map()
+		if !secret.IsSecret(obj) || !secret.HasEncryptedValues(obj) {
+			continue
+		}
+
+		if c == nil {
+			key, err := secret.SiteKey()
+			if err != nil {
+				return err
+			}
+			if c, err = secret.NewCipher(key); err != nil {
+				return err
+			}
+		}
+
+		if err := c.DecryptSecret(obj); err != nil {
+			return ErrDecryption{Resource: res.CurId().String(), Err: err}
+		}
+		res.SetMap(obj)
+	}
+	return nil
+}
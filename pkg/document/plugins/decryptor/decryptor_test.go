@@ -0,0 +1,58 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/secret"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestDecryptor(t *testing.T) {
+	defer testutil.SetEnv(t, secret.SiteKeyEnv, "test-site-key")()
+
+	bundle, err := document.NewBundle(testutil.SetupTestFs(t, "testdata/encrypted"), "/", "/")
+	require.NoError(t, err)
+
+	doc, err := bundle.SelectOne(document.NewSelector().ByKind("Secret").ByName("node01-bmc-secret"))
+	require.NoError(t, err)
+	password, err := doc.GetString("data.password")
+	require.NoError(t, err)
+	assert.Equal(t, "cGFzc3cwcmQ=", password)
+	username, err := doc.GetString("stringData.username")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", username)
+
+	doc, err = bundle.SelectOne(document.NewSelector().ByKind("Secret").ByName("node02-bmc-secret"))
+	require.NoError(t, err)
+	username, err = doc.GetString("stringData.username")
+	require.NoError(t, err)
+	assert.Equal(t, "root", username)
+}
+
+func TestDecryptorWrongKey(t *testing.T) {
+	defer testutil.SetEnv(t, secret.SiteKeyEnv, "wrong-site-key")()
+
+	_, err := document.NewBundle(testutil.SetupTestFs(t, "testdata/encrypted"), "/", "/")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), secret.ErrDecryptionFailed{}.Error())
+}
@@ -17,19 +17,33 @@ limitations under the License.
 package plugins
 
 import (
+	"sort"
+	"strings"
+
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/document/plugins/decryptor"
+	"opendev.org/airship/airshipctl/pkg/document/plugins/replacement"
+	"opendev.org/airship/airshipctl/pkg/document/plugins/templater"
 )
 
-// PluginRegistry map of plugin kinds to plugin instance
-var PluginRegistry = make(map[string]resmap.TransformerPlugin)
+// PluginRegistry map of plugin kinds to functions creating plugin instances.
+// Each plugin configuration document gets its own instance
+var PluginRegistry = map[string]func() resmap.TransformerPlugin{
+	decryptor.Kind:   decryptor.New,
+	replacement.Kind: replacement.New,
+	templater.Kind:   templater.New,
+}
 
 // TransformerLoader airship document plugin loader. Loads external
 // Kustomize plugins as builtin
 type TransformerLoader struct {
 	resid.ResId
+
+	plugin resmap.TransformerPlugin
 }
 
 // Config reads plugin configuration structure
@@ -38,23 +52,33 @@ func (l *TransformerLoader) Config(
 	if err := yaml.Unmarshal(c, l); err != nil {
 		return err
 	}
-	airshipPlugin, found := PluginRegistry[l.Kind]
+	newPlugin, found := PluginRegistry[l.Kind]
 	if !found {
 		return ErrUnknownPlugin{Kind: l.Kind}
 	}
-	return airshipPlugin.Config(ldr, rf, c)
+	l.plugin = newPlugin()
+	return l.plugin.Config(ldr, rf, c)
 }
 
 // Transform executes Transform method of an external plugin
 func (l *TransformerLoader) Transform(m resmap.ResMap) error {
-	airshipPlugin, found := PluginRegistry[l.Kind]
-	if !found {
+	if l.plugin == nil {
 		return ErrUnknownPlugin{Kind: l.Kind}
 	}
-	return airshipPlugin.Transform(m)
+	return l.plugin.Transform(m)
 }
 
 // NewTransformerLoader returns plugin loader instance
 func NewTransformerLoader() resmap.TransformerPlugin {
 	return &TransformerLoader{}
 }
+
+// Fingerprint identifies the set of registered plugins
+func Fingerprint() string {
+	kinds := make([]string, 0, len(PluginRegistry))
+	for kind := range PluginRegistry {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return strings.Join(kinds, ",")
+}
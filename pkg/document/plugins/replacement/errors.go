@@ -0,0 +1,79 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replacement
+
+import (
+	"fmt"
+)
+
+// ErrBadConfiguration returned if the replacement is not configured properly
+type ErrBadConfiguration struct {
+	Msg string
+}
+
+func (e ErrBadConfiguration) Error() string {
+	return e.Msg
+}
+
+// ErrSourceNotFound returned if the source selector matches no documents
+type ErrSourceNotFound struct {
+	ObjRef interface{}
+}
+
+func (e ErrSourceNotFound) Error() string {
+	return fmt.Sprintf("Replacement source %v matches no documents", e.ObjRef)
+}
+
+// ErrMultipleSources returned if the source selector matches several documents
+type ErrMultipleSources struct {
+	ObjRef interface{}
+}
+
+func (e ErrMultipleSources) Error() string {
+	return fmt.Sprintf("Replacement source %v matches more than one document", e.ObjRef)
+}
+
+// ErrTargetNotFound returned if the target selector matches no documents
+type ErrTargetNotFound struct {
+	ObjRef interface{}
+}
+
+func (e ErrTargetNotFound) Error() string {
+	return fmt.Sprintf("Replacement target %v matches no documents", e.ObjRef)
+}
+
+// ErrFieldNotFound returned if the field path can't be resolved in the document
+type ErrFieldNotFound struct {
+	Document string
+	Path     string
+}
+
+func (e ErrFieldNotFound) Error() string {
+	return fmt.Sprintf("Document %s has no field %q", e.Document, e.Path)
+}
+
+// ErrPatternSubstitution returned if the pattern can't be substituted in
+// the target field
+type ErrPatternSubstitution struct {
+	Document string
+	Path     string
+	Msg      string
+}
+
+func (e ErrPatternSubstitution) Error() string {
+	return fmt.Sprintf("Document %s field %q: %s", e.Document, e.Path, e.Msg)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replacement
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// step is a single element of a field path, either a map key or a list item
+// selected by index or by a field value
+type step struct {
+	key   string
+	list  bool
+	index int
+	field string
+	value string
+}
+
+// parsePath parses field path like spec.containers[name=nginx].ports[0]
+func parsePath(path string) ([]step, error) {
+	var steps []step
+	rest := path
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, ErrBadConfiguration{Msg: fmt.Sprintf("unterminated list selector in field path %q", path)}
+			}
+			selector := rest[1:end]
+			rest = rest[end+1:]
+
+			if eq := strings.IndexByte(selector, '='); eq >= 0 {
+				steps = append(steps, step{list: true, field: selector[:eq], value: selector[eq+1:]})
+				continue
+			}
+			index, err := strconv.Atoi(selector)
+			if err != nil || index < 0 {
+				return nil, ErrBadConfiguration{Msg: fmt.Sprintf("invalid list selector %q in field path %q", selector, path)}
+			}
+			steps = append(steps, step{list: true, index: index})
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			steps = append(steps, step{key: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+
+	if len(steps) == 0 {
+		return nil, ErrBadConfiguration{Msg: fmt.Sprintf("empty field path %q", path)}
+	}
+	return steps, nil
+}
+
+// getField returns the value at path
+func getField(obj interface{}, path []step) (interface{}, bool) {
+	current := obj
+	for _, s := range path {
+		next, found := child(current, s)
+		if !found {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+// setField sets the value at path creating missing maps on the way, missing
+// list items are never created
+func setField(obj map[string]interface{}, path []step, value interface{}) bool {
+	var current interface{} = obj
+	for i, s := range path[:len(path)-1] {
+		next, found := child(current, s)
+		if !found || next == nil {
+			parent, isMap := current.(map[string]interface{})
+			if s.list || !isMap || path[i+1].list {
+				return false
+			}
+			next = map[string]interface{}{}
+			parent[s.key] = next
+		}
+		current = next
+	}
+
+	last := path[len(path)-1]
+	if !last.list {
+		parent, ok := current.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		parent[last.key] = value
+		return true
+	}
+
+	list, ok := current.([]interface{})
+	if !ok {
+		return false
+	}
+	i := itemIndex(list, last)
+	if i < 0 {
+		return false
+	}
+	list[i] = value
+	return true
+}
+
+func child(current interface{}, s step) (interface{}, bool) {
+	if !s.list {
+		parent, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, found := parent[s.key]
+		return value, found
+	}
+
+	list, ok := current.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	i := itemIndex(list, s)
+	if i < 0 {
+		return nil, false
+	}
+	return list[i], true
+}
+
+// itemIndex returns index of the list item matching the step or -1
+func itemIndex(list []interface{}, s step) int {
+	if s.field == "" {
+		if s.index < len(list) {
+			return s.index
+		}
+		return -1
+	}
+
+	for i, item := range list {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if value, found := fields[s.field]; found && fmt.Sprint(value) == s.value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replacement
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// New returns replacement transformer plugin instance
+func New() resmap.TransformerPlugin {
+	return &Transformer{}
+}
+
+// Config reads replacements from the plugin configuration document
+func (t *Transformer) Config(_ ifc.Loader, _ *resmap.Factory, c []byte) error {
+	t.Replacements = nil
+	if err := yaml.Unmarshal(c, t); err != nil {
+		return err
+	}
+
+	for _, r := range t.Replacements {
+		switch {
+		case r.Source.ObjRef != nil && r.Source.Value != "":
+			return ErrBadConfiguration{Msg: "replacement source must have either objref or value, not both"}
+		case r.Source.ObjRef != nil && r.Source.FieldRef == "":
+			return ErrBadConfiguration{Msg: "replacement source with objref must have fieldref"}
+		case r.Target.ObjRef == nil:
+			return ErrBadConfiguration{Msg: "replacement target must have objref"}
+		case len(r.Target.FieldRefs) == 0:
+			return ErrBadConfiguration{Msg: "replacement target must have fieldrefs"}
+		}
+	}
+	return nil
+}
+
+// Transform copies source values into target fields of the resources
+func (t *Transformer) Transform(m resmap.ResMap) error {
+	for _, r := range t.Replacements {
+		value, err := sourceValue(m, r.Source)
+		if err != nil {
+			return err
+		}
+
+		targets, err := m.Select(*r.Target.ObjRef)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return ErrTargetNotFound{ObjRef: *r.Target.ObjRef}
+		}
+
+		for _, res := range targets {
+			if err = replace(res, r.Target.FieldRefs, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sourceValue(m resmap.ResMap, source Source) (interface{}, error) {
+	if source.ObjRef == nil {
+		return source.Value, nil
+	}
+
+	sources, err := m.Select(*source.ObjRef)
+	if err != nil {
+		return nil, err
+	}
+	switch len(sources) {
+	case 0:
+		return nil, ErrSourceNotFound{ObjRef: *source.ObjRef}
+	case 1:
+	default:
+		return nil, ErrMultipleSources{ObjRef: *source.ObjRef}
+	}
+
+	path, err := parsePath(source.FieldRef)
+	if err != nil {
+		return nil, err
+	}
+	value, found := getField(sources[0].Map(), path)
+	if !found {
+		return nil, ErrFieldNotFound{Document: sources[0].CurId().String(), Path: source.FieldRef}
+	}
+	return deepCopy(value), nil
+}
+
+// replace sets fields of the resource to the value
+func replace(res *resource.Resource, fieldRefs []string, value interface{}) error {
+	obj := res.Map()
+	for _, fieldRef := range fieldRefs {
+		ref, pattern, err := splitPattern(fieldRef)
+		if err != nil {
+			return err
+		}
+		path, err := parsePath(ref)
+		if err != nil {
+			return err
+		}
+
+		newValue := deepCopy(value)
+		if pattern != "" {
+			if newValue, err = substitute(obj, path, pattern, value); err != nil {
+				return ErrPatternSubstitution{Document: res.CurId().String(), Path: fieldRef, Msg: err.Error()}
+			}
+		}
+
+		if !setField(obj, path, newValue) {
+			return ErrFieldNotFound{Document: res.CurId().String(), Path: ref}
+		}
+	}
+	res.SetMap(obj)
+	return nil
+}
+
+// substitute returns the string field at path with the pattern replaced by
+// the value
+func substitute(obj map[string]interface{}, path []step, pattern string, value interface{}) (string, error) {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}, nil:
+		return "", fmt.Errorf("source value %v can't be substituted into a string", value)
+	}
+
+	field, found := getField(obj, path)
+	if !found {
+		return "", fmt.Errorf("field is not found")
+	}
+	current, ok := field.(string)
+	if !ok {
+		return "", fmt.Errorf("field value %v is not a string", field)
+	}
+	if !strings.Contains(current, pattern) {
+		return "", fmt.Errorf("pattern %q is not found in %q", pattern, current)
+	}
+	return strings.ReplaceAll(current, pattern, fmt.Sprint(value)), nil
+}
+
+// splitPattern splits field reference like spec.image%TAG% to the field path
+// and the pattern, pattern is empty if the reference has none
+func splitPattern(fieldRef string) (string, string, error) {
+	i := strings.IndexByte(fieldRef, '%')
+	if i < 0 {
+		return fieldRef, "", nil
+	}
+	pattern := fieldRef[i:]
+	if len(pattern) < 3 || !strings.HasSuffix(pattern, "%") || strings.Count(pattern, "%") != 2 {
+		return "", "", ErrBadConfiguration{Msg: fmt.Sprintf("invalid substitution pattern in %q", fieldRef)}
+	}
+	return fieldRef[:i], pattern[1 : len(pattern)-1], nil
+}
+
+// deepCopy copies maps and lists of the value so that the source and targets
+// don't share them
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
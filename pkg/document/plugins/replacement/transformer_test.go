@@ -0,0 +1,83 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replacement_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestReplacement(t *testing.T) {
+	bundle, err := document.NewBundle(testutil.SetupTestFs(t, "testdata/valid"), "/", "/")
+	require.NoError(t, err)
+
+	nginx, err := bundle.SelectOne(document.NewSelector().ByKind("Deployment").ByName("nginx"))
+	require.NoError(t, err)
+	assert.Equal(t, "frontend", nginx.GetLabels()["tier"])
+
+	spec, err := nginx.GetMap("spec.template.spec")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "frontend", "image": "busybox"},
+		map[string]interface{}{"name": "nginx", "image": "nginx:1.17.8"},
+	}, spec["containers"])
+	assert.Equal(t, map[string]interface{}{
+		"nameservers": []interface{}{"8.8.8.8", "8.8.4.4"},
+	}, spec["dnsConfig"])
+
+	proxy, err := bundle.SelectOne(document.NewSelector().ByKind("Deployment").ByName("proxy"))
+	require.NoError(t, err)
+	spec, err = proxy.GetMap("spec.template.spec")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"nameservers": []interface{}{"8.8.8.8", "8.8.4.4"},
+	}, spec["dnsConfig"])
+}
+
+func TestReplacementErrors(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		expectedErr string
+	}{
+		{
+			fixture:     "testdata/missing-source",
+			expectedErr: "matches no documents",
+		},
+		{
+			fixture:     "testdata/missing-field",
+			expectedErr: `has no field "spec.template.spec.containers[name=proxy].image"`,
+		},
+		{
+			fixture:     "testdata/bad-config",
+			expectedErr: "replacement target must have objref",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.fixture, func(t *testing.T) {
+			_, err := document.NewBundle(testutil.SetupTestFs(t, tt.fixture), "/", "/")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replacement
+
+import (
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// Kind of the replacement transformer configuration document
+const Kind = "ReplacementTransformer"
+
+// Transformer copies values from source documents into fields of
+// target documents
+type Transformer struct {
+	Replacements []Replacement `json:"replacements"`
+}
+
+// Replacement describes a single value copied from the source to targets
+type Replacement struct {
+	Source Source `json:"source"`
+	Target Target `json:"target"`
+}
+
+// Source is the value to copy, either the literal Value or the value of
+// FieldRef in the single document selected by ObjRef
+type Source struct {
+	ObjRef   *types.Selector `json:"objref,omitempty"`
+	FieldRef string          `json:"fieldref,omitempty"`
+	Value    string          `json:"value,omitempty"`
+}
+
+// Target lists fields of documents selected by ObjRef receiving the value.
+// Field paths are dot separated, list items are selected either by index
+// as in containers[0] or by a field value as in containers[name=nginx].
+// A path ending with %PATTERN% replaces PATTERN within the string value of
+// the field instead of the whole field
+type Target struct {
+	ObjRef    *types.Selector `json:"objref"`
+	FieldRefs []string        `json:"fieldrefs"`
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templater
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/yaml"
+)
+
+// Kind of the templater configuration document
+const Kind = "Templater"
+
+// nonRepeatableFunctions are sprig functions generating random values that
+// hermetic sprig functions still include, templates must render the same
+// documents every time for bundles to be reproducible and cacheable
+var nonRepeatableFunctions = []string{
+	"ago",
+	"encryptAES",
+	"genCA",
+	"genPrivateKey",
+	"genSelfSignedCert",
+	"genSignedCert",
+	"htpasswd",
+	"shuffle",
+}
+
+// Templater renders the Go template with values and appends the resulting
+// documents to the bundle. Template can use sprig functions that don't
+// depend on time, environment or randomness
+type Templater struct {
+	Values   map[string]interface{} `json:"values,omitempty"`
+	Template string                 `json:"template"`
+
+	tmpl *template.Template
+	rf   *resmap.Factory
+}
+
+// New returns templater plugin instance
+func New() resmap.TransformerPlugin {
+	return &Templater{}
+}
+
+// Config reads values and the template from the plugin configuration document
+func (t *Templater) Config(_ ifc.Loader, rf *resmap.Factory, c []byte) error {
+	if err := yaml.Unmarshal(c, t); err != nil {
+		return err
+	}
+	if t.Template == "" {
+		return ErrBadConfiguration{Msg: "templater must have template"}
+	}
+
+	tmpl, err := template.New(Kind).
+		Funcs(funcMap()).
+		Option("missingkey=error").
+		Parse(t.Template)
+	if err != nil {
+		return err
+	}
+	t.tmpl = tmpl
+	t.rf = rf
+	return nil
+}
+
+// funcMap returns sprig functions rendering the same output for the same input
+func funcMap() template.FuncMap {
+	funcs := sprig.HermeticTxtFuncMap()
+	for _, name := range nonRepeatableFunctions {
+		delete(funcs, name)
+	}
+	return funcs
+}
+
+// Transform appends documents rendered from the template to the resources
+func (t *Templater) Transform(m resmap.ResMap) error {
+	out := &bytes.Buffer{}
+	if err := t.tmpl.Execute(out, t.Values); err != nil {
+		return err
+	}
+
+	generated, err := t.rf.NewResMapFromBytes(out.Bytes())
+	if err != nil {
+		return err
+	}
+	return m.AppendAll(generated)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templater_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/plugins/templater"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestTemplater(t *testing.T) {
+	bundle, err := document.NewBundle(testutil.SetupTestFs(t, "testdata/hosts"), "/", "/")
+	require.NoError(t, err)
+
+	docs, err := bundle.GetAllDocuments()
+	require.NoError(t, err)
+	assert.Len(t, docs, 5)
+
+	hosts, err := bundle.GetByGvk("metal3.io", "v1alpha1", "BareMetalHost")
+	require.NoError(t, err)
+	require.Len(t, hosts, 2)
+
+	host, err := bundle.SelectOne(document.NewSelector().ByKind("BareMetalHost").ByName("node02"))
+	require.NoError(t, err)
+	address, err := host.GetString("spec.bmc.address")
+	require.NoError(t, err)
+	assert.Equal(t, "redfish+http://10.23.25.2:8000/redfish/v1/Systems/node02", address)
+
+	username, password, err := document.GetBMHBMCCredentials(host, bundle)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", username)
+	assert.Equal(t, "passw0rd", password)
+}
+
+func TestTemplaterErrors(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		expectedErr string
+	}{
+		{
+			fixture:     "testdata/missing-value",
+			expectedErr: `map has no entry for key "name"`,
+		},
+		{
+			fixture:     "testdata/conflict",
+			expectedErr: "metal3",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.fixture, func(t *testing.T) {
+			_, err := document.NewBundle(testutil.SetupTestFs(t, tt.fixture), "/", "/")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestTemplaterNonRepeatableFunctions(t *testing.T) {
+	for _, fn := range []string{"env \"HOME\"", "now", "randAlpha 8", "uuidv4", "genPrivateKey \"rsa\"", "genCA \"ca\" 365"} {
+		fn := fn
+		t.Run(fn, func(t *testing.T) {
+			config := []byte("template: '{{ " + fn + " }}'")
+			err := templater.New().Config(nil, nil, config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "not defined")
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/document/diff"
+)
+
+// ErrInvalidRule returned if the policy rule can not be evaluated
+type ErrInvalidRule struct {
+	Rule    string
+	Message string
+}
+
+func (e ErrInvalidRule) Error() string {
+	return fmt.Sprintf("Policy rule %q is invalid: %s", e.Rule, e.Message)
+}
+
+// Violation describes a document not complying with the policy rule
+type Violation struct {
+	Rule    string
+	ID      diff.ID
+	Message string
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("%s: %s (rule %s)", v.ID, v.Message, v.Rule)
+}
+
+// ErrPolicyViolation returned if any of the documents does not comply with policy rules
+type ErrPolicyViolation struct {
+	Violations []Violation
+}
+
+func (e ErrPolicyViolation) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Error())
+	}
+	return fmt.Sprintf("Documents violate policy rules:\n%s", strings.Join(messages, "\n"))
+}
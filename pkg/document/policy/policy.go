@@ -0,0 +1,279 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/diff"
+	"opendev.org/airship/airshipctl/pkg/document/filter"
+)
+
+// defaultNamespace is the namespace of namespaced documents with no namespace set
+const defaultNamespace = "default"
+
+// builtinClusterScopedKinds are kinds of Kubernetes resources that have no namespace
+var builtinClusterScopedKinds = []string{
+	"APIService",
+	"CertificateSigningRequest",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"ComponentStatus",
+	"CSIDriver",
+	"CSINode",
+	"CustomResourceDefinition",
+	"MutatingWebhookConfiguration",
+	"Namespace",
+	"Node",
+	"PersistentVolume",
+	"PodSecurityPolicy",
+	"PriorityClass",
+	"RuntimeClass",
+	"StorageClass",
+	"ValidatingWebhookConfiguration",
+	"VolumeAttachment",
+}
+
+// Load returns rules of every policy document found in the bundle
+func Load(bundle document.Bundle) ([]Rule, error) {
+	docs, err := bundle.GetByGvk(Group, Version, Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	for _, doc := range docs {
+		data, err := doc.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		policy := &Policy{}
+		if err = json.Unmarshal(data, policy); err != nil {
+			return nil, err
+		}
+		rules = append(rules, policy.Rules...)
+	}
+	return rules, nil
+}
+
+// Enforce checks documents of the bundle against policy rules found in it
+// and returns ErrPolicyViolation if any rule is violated
+func Enforce(bundle document.Bundle) error {
+	violations, err := Check(bundle)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return ErrPolicyViolation{Violations: violations}
+	}
+	return nil
+}
+
+// Check returns violations of policy rules found in the bundle by its documents
+func Check(bundle document.Bundle) ([]Violation, error) {
+	rules, err := Load(bundle)
+	if err != nil {
+		return nil, err
+	}
+	return CheckRules(bundle, rules)
+}
+
+// CheckRules returns violations of the rules by documents of the bundle,
+// policy documents themselves are not checked
+func CheckRules(bundle document.Bundle, rules []Rule) ([]Violation, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	all, err := bundle.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]bool, len(all))
+	for _, doc := range all {
+		index[referenceKey(doc.GetKind(), doc.GetNamespace(), doc.GetName())] = true
+	}
+	clusterScoped := clusterScopedKinds(all)
+
+	var violations []Violation
+	for _, rule := range rules {
+		checker, err := newRuleChecker(rule)
+		if err != nil {
+			return nil, err
+		}
+		docs := all
+		if checker.selector != nil {
+			if docs, err = checker.selector.Select(bundle); err != nil {
+				return nil, err
+			}
+		}
+		for _, doc := range docs {
+			if isPolicy(doc) {
+				continue
+			}
+			docViolations, err := checker.check(doc, index, clusterScoped)
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, docViolations...)
+		}
+	}
+	return violations, nil
+}
+
+// ruleChecker holds the rule along with its parsed selector and name pattern
+type ruleChecker struct {
+	Rule
+	selector    *filter.Expression
+	namePattern *regexp.Regexp
+}
+
+func newRuleChecker(rule Rule) (*ruleChecker, error) {
+	if rule.Name == "" {
+		return nil, ErrInvalidRule{Message: "name must not be empty"}
+	}
+	checker := &ruleChecker{Rule: rule}
+
+	var err error
+	if rule.Select != "" {
+		if checker.selector, err = filter.Parse(rule.Select); err != nil {
+			return nil, ErrInvalidRule{Rule: rule.Name, Message: err.Error()}
+		}
+	}
+	if rule.NamePattern != "" {
+		if checker.namePattern, err = regexp.Compile(rule.NamePattern); err != nil {
+			return nil, ErrInvalidRule{Rule: rule.Name, Message: err.Error()}
+		}
+	}
+	for _, ref := range rule.References {
+		if ref.Field == "" || ref.Kind == "" {
+			return nil, ErrInvalidRule{Rule: rule.Name, Message: "reference must have field and kind"}
+		}
+	}
+	return checker, nil
+}
+
+func (c *ruleChecker) check(doc document.Document, index, clusterScoped map[string]bool) ([]Violation, error) {
+	id, err := diff.NewID(doc)
+	if err != nil {
+		return nil, err
+	}
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]interface{}{}
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	var violations []Violation
+	violate := func(format string, args ...interface{}) {
+		violations = append(violations, Violation{Rule: c.Name, ID: id, Message: fmt.Sprintf(format, args...)})
+	}
+
+	labels := doc.GetLabels()
+	for _, key := range c.RequiredLabels {
+		if _, found := labels[key]; !found {
+			violate("missing label %q", key)
+		}
+	}
+	annotations := doc.GetAnnotations()
+	for _, key := range c.RequiredAnnotations {
+		if _, found := annotations[key]; !found {
+			violate("missing annotation %q", key)
+		}
+	}
+	for _, path := range c.RequiredFields {
+		if _, found := lookupField(obj, path); !found {
+			violate("missing field %q", path)
+		}
+	}
+	for _, path := range c.ForbiddenFields {
+		if _, found := lookupField(obj, path); found {
+			violate("forbidden field %q is set", path)
+		}
+	}
+	// namespaced documents with no namespace are deployed to the default one
+	docNamespace := doc.GetNamespace()
+	if docNamespace == "" && !clusterScoped[doc.GetKind()] {
+		docNamespace = defaultNamespace
+	}
+	for _, namespace := range c.ForbiddenNamespaces {
+		if docNamespace == namespace {
+			violate("namespace %q is forbidden", namespace)
+		}
+	}
+	if c.namePattern != nil && !c.namePattern.MatchString(doc.GetName()) {
+		violate("name does not match pattern %q", c.NamePattern)
+	}
+	for _, ref := range c.References {
+		value, found := lookupField(obj, ref.Field)
+		if !found {
+			continue
+		}
+		name, ok := value.(string)
+		if !ok {
+			violate("field %q must be a string", ref.Field)
+			continue
+		}
+		namespace := doc.GetNamespace()
+		if ref.NamespaceField != "" {
+			if value, found := lookupField(obj, ref.NamespaceField); found {
+				namespace = fmt.Sprintf("%v", value)
+			}
+		}
+		if !index[referenceKey(ref.Kind, namespace, name)] {
+			violate("%s %q referenced by %s not found", ref.Kind, name, ref.Field)
+		}
+	}
+	return violations, nil
+}
+
+// clusterScopedKinds returns kinds of built-in cluster scoped resources along
+// with kinds of cluster scoped custom resources defined in the documents
+func clusterScopedKinds(docs []document.Document) map[string]bool {
+	kinds := make(map[string]bool, len(builtinClusterScopedKinds))
+	for _, kind := range builtinClusterScopedKinds {
+		kinds[kind] = true
+	}
+	for _, doc := range docs {
+		if doc.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		scope, err := doc.GetString("spec.scope")
+		if err != nil || scope != "Cluster" {
+			continue
+		}
+		if kind, err := doc.GetString("spec.names.kind"); err == nil {
+			kinds[kind] = true
+		}
+	}
+	return kinds
+}
+
+// lookupField returns value of the field by its dot separated path
+func lookupField(obj map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = obj
+	for _, key := range strings.Split(path, ".") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = fields[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func referenceKey(kind, namespace, name string) string {
+	return kind + "|" + namespace + "|" + name
+}
+
+func isPolicy(doc document.Document) bool {
+	apiVersion, err := doc.GetString("apiVersion")
+	return err == nil && doc.GetKind() == Kind && apiVersion == Group+"/"+Version
+}
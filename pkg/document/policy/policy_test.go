@@ -0,0 +1,94 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document/policy"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestLoad(t *testing.T) {
+	rules, err := policy.Load(testutil.NewTestBundle(t, "testdata/violations"))
+	require.NoError(t, err)
+	require.Len(t, rules, 4)
+	assert.Equal(t, policy.Rule{
+		Name:           "secret-phase-label",
+		Description:    "every Secret must carry a phase label",
+		Select:         "kind=Secret",
+		RequiredLabels: []string{"airshipit.org/phase"},
+	}, rules[0])
+	assert.Equal(t, []policy.Reference{{Field: "spec.bmc.credentialsName", Kind: "Secret"}}, rules[1].References)
+}
+
+func TestCheck(t *testing.T) {
+	violations, err := policy.Check(testutil.NewTestBundle(t, "testdata/violations"))
+	require.NoError(t, err)
+
+	actual := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		actual = append(actual, violation.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		`v1 Secret default/node-2-bmc-secret: missing label "airshipit.org/phase" (rule secret-phase-label)`,
+		`metal3.io/v1alpha1 BareMetalHost test/node-2: Secret "node-2-bmc-secret" referenced by ` +
+			`spec.bmc.credentialsName not found (rule bmh-credentials)`,
+		`metal3.io/v1alpha1 BareMetalHost test/master: missing field "spec.bmc.credentialsName" (rule bmh-credentials)`,
+		`metal3.io/v1alpha1 BareMetalHost test/master: name does not match pattern "^node-[0-9]+$" (rule bmh-naming)`,
+		`v1 Secret default/node-2-bmc-secret: namespace "default" is forbidden (rule no-default-namespace)`,
+		`v1 ConfigMap node-config: namespace "default" is forbidden (rule no-default-namespace)`,
+	}, actual)
+}
+
+func TestEnforce(t *testing.T) {
+	assert.NoError(t, policy.Enforce(testutil.NewTestBundle(t, "testdata/valid")))
+
+	err := policy.Enforce(testutil.NewTestBundle(t, "testdata/violations"))
+	require.IsType(t, policy.ErrPolicyViolation{}, err)
+	assert.Len(t, err.(policy.ErrPolicyViolation).Violations, 6)
+}
+
+func TestCheckRulesInvalid(t *testing.T) {
+	bundle := testutil.NewTestBundle(t, "testdata/valid")
+
+	tests := []struct {
+		name        string
+		rule        policy.Rule
+		expectedErr error
+	}{
+		{
+			name:        "missing name",
+			rule:        policy.Rule{Select: "kind=Secret"},
+			expectedErr: policy.ErrInvalidRule{Message: "name must not be empty"},
+		},
+		{
+			name: "bad name pattern",
+			rule: policy.Rule{Name: "bad-pattern", NamePattern: "node-["},
+			expectedErr: policy.ErrInvalidRule{
+				Rule:    "bad-pattern",
+				Message: "error parsing regexp: missing closing ]: `[`",
+			},
+		},
+		{
+			name: "incomplete reference",
+			rule: policy.Rule{Name: "bad-reference", References: []policy.Reference{{Field: "spec.bmc.credentialsName"}}},
+			expectedErr: policy.ErrInvalidRule{
+				Rule:    "bad-reference",
+				Message: "reference must have field and kind",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := policy.CheckRules(bundle, []policy.Rule{tt.rule})
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+
+	_, err := policy.CheckRules(bundle, []policy.Rule{{Name: "bad-select", Select: "kind="}})
+	assert.IsType(t, policy.ErrInvalidRule{}, err)
+}
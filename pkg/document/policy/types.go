@@ -0,0 +1,54 @@
+package policy
+
+// Group, version and kind of documents declaring policy rules
+const (
+	Group   = "airshipit.org"
+	Version = "v1alpha1"
+	Kind    = "Policy"
+)
+
+// Policy is a document holding rules every document of the bundle must
+// comply with. Policy documents are not meant to be deployed, so they
+// should be annotated with config.kubernetes.io/local-config: "true"
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule describes requirements for documents of the bundle
+type Rule struct {
+	// Name identifies the rule in violation reports
+	Name string `json:"name"`
+	// Description explains the purpose of the rule
+	Description string `json:"description,omitempty"`
+	// Select is a filter expression selecting documents the rule applies
+	// to, e.g. kind=Secret, every document is checked if it is empty
+	Select string `json:"select,omitempty"`
+	// RequiredLabels lists label keys every selected document must have
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+	// RequiredAnnotations lists annotation keys every selected document must have
+	RequiredAnnotations []string `json:"requiredAnnotations,omitempty"`
+	// RequiredFields lists dot separated paths of fields every selected document must have
+	RequiredFields []string `json:"requiredFields,omitempty"`
+	// ForbiddenFields lists dot separated paths of fields selected documents must not have
+	ForbiddenFields []string `json:"forbiddenFields,omitempty"`
+	// ForbiddenNamespaces lists namespaces selected documents must not use
+	ForbiddenNamespaces []string `json:"forbiddenNamespaces,omitempty"`
+	// NamePattern is a regular expression names of selected documents must match
+	NamePattern string `json:"namePattern,omitempty"`
+	// References lists fields of selected documents that must hold a name
+	// of a document existing in the bundle
+	References []Reference `json:"references,omitempty"`
+}
+
+// Reference describes a field holding a name of another document, e.g.
+// spec.bmc.credentialsName of BareMetalHost referencing a Secret
+type Reference struct {
+	// Field is a dot separated path of the field holding the name
+	Field string `json:"field"`
+	// Kind is the kind of the referenced document
+	Kind string `json:"kind"`
+	// NamespaceField is a dot separated path of the field holding the
+	// namespace of referenced document, namespace of the referencing
+	// document is used if it is empty
+	NamespaceField string `json:"namespaceField,omitempty"`
+}
@@ -0,0 +1,38 @@
+package pull
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrPullFailed returned if any of the repositories failed to pull, errors
+// are mapped by repository names
+type ErrPullFailed struct {
+	Errors map[string]error
+}
+
+func (e ErrPullFailed) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, 0, len(names))
+	for _, name := range names {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", name, e.Errors[name]))
+	}
+	return "Failed to pull repositories: " + strings.Join(msgs, "; ")
+}
+
+// ErrDuplicateTargetDir returned if repositories of the manifest would be
+// pulled into the same directory of the target path
+type ErrDuplicateTargetDir struct {
+	Dir          string
+	Repositories []string
+}
+
+func (e ErrDuplicateTargetDir) Error() string {
+	return fmt.Sprintf("Repositories %s are pulled into the same directory %s, their URLs must end with different names",
+		strings.Join(e.Repositories, ", "), e.Dir)
+}
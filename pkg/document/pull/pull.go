@@ -1,16 +1,38 @@
 package pull
 
 import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document/lock"
 	"opendev.org/airship/airshipctl/pkg/document/repo"
 	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/util"
 )
 
+// DefaultJobs is the number of repositories pulled concurrently by default
+const DefaultJobs = 4
+
 type Settings struct {
 	*environment.AirshipCTLSettings
+	// Update resolves checkout options of repositories anew instead of
+	// checking out commits recorded in the lock file
+	Update bool
+	// Jobs is the number of repositories pulled concurrently, DefaultJobs
+	// is used if it is not positive
+	Jobs int
 }
 
-func (s *Settings) Pull() error {
-	err := s.cloneRepositories()
+// Pull pulls repositories of the current manifest and writes their progress to out
+func (s *Settings) Pull(out io.Writer) error {
+	err := s.cloneRepositories(out)
 	if err != nil {
 		return err
 	}
@@ -18,25 +40,203 @@ func (s *Settings) Pull() error {
 	return nil
 }
 
-func (s *Settings) cloneRepositories() error {
-	// Clone main repository
+// pullResult is the outcome of pulling a single repository
+type pullResult struct {
+	name   string
+	commit string
+	err    error
+}
+
+// cloneRepositories pulls repositories concurrently at commits of the lock
+// file and records commits they end up at in the lock file. Repositories
+// failing to pull keep their previous lock entries
+func (s *Settings) cloneRepositories(out io.Writer) error {
 	currentManifest, err := s.Config().CurrentContextManifest()
 	if err != nil {
 		return err
 	}
+	if _, exists := currentManifest.Repositories[currentManifest.PrimaryRepositoryName]; !exists {
+		return config.ErrMissingPrimaryRepo{}
+	}
+	names := repositoryNames(currentManifest)
+	if err = checkTargetDirs(currentManifest, names); err != nil {
+		return err
+	}
 
-	// Clone repositories
-	for _, extraRepoConfig := range currentManifest.Repositories {
-		repository, err := repo.NewRepository(currentManifest.TargetPath, extraRepoConfig)
-		if err != nil {
+	lockPath := lock.Path(currentManifest)
+	previous, err := lock.Read(lockPath)
+	if err != nil {
+		// the lock file is rewritten on update, it doesn't have to be valid
+		if !s.Update {
 			return err
 		}
-		err = repository.Download(extraRepoConfig.ToCheckoutOptions(true).Force)
-		if err != nil {
-			return err
+		log.Debugf("Ignoring the lock file: %v", err)
+		previous = lock.New()
+	}
+
+	jobs := make(chan string)
+	results := make(chan pullResult, len(names))
+	progress := &progressWriter{out: out}
+	var wg sync.WaitGroup
+	for i := 0; i < s.jobs(len(names)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				repoConfig := currentManifest.Repositories[name]
+				if commit, locked := previous.Commit(name, repoConfig); locked && !s.Update {
+					log.Debugf("Repository %s is locked at %s", name, commit)
+					repoConfig = lockedRepository(repoConfig, commit)
+				}
+				progress.printf("%s: pulling\n", name)
+				commit, err := pullRepository(currentManifest.TargetPath, repoConfig)
+				if err == nil {
+					progress.printf("%s: checked out at %s\n", name, commit)
+				}
+				results <- pullResult{name: name, commit: commit, err: err}
+			}
+		}()
+	}
+	for _, name := range names {
+		jobs <- name
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	current := lock.New()
+	failed := make(map[string]error)
+	for result := range results {
+		repoConfig := currentManifest.Repositories[result.name]
+		if result.err == nil {
+			current.Set(result.name, repoConfig, result.commit)
+			continue
+		}
+		failed[result.name] = result.err
+		if commit, locked := previous.Commit(result.name, repoConfig); locked {
+			current.Set(result.name, repoConfig, commit)
 		}
-		repository.Driver.Close()
 	}
 
+	if err = current.Write(lockPath); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return ErrPullFailed{Errors: failed}
+	}
 	return nil
 }
+
+// jobs returns the number of workers pulling the given number of repositories
+func (s *Settings) jobs(repositories int) int {
+	jobs := s.Jobs
+	if jobs <= 0 {
+		jobs = DefaultJobs
+	}
+	if jobs > repositories {
+		jobs = repositories
+	}
+	return jobs
+}
+
+// repositoryNames returns names of repositories of the manifest, the primary
+// repository goes first since the bundle is built from it
+func repositoryNames(manifest *config.Manifest) []string {
+	names := make([]string, 0, len(manifest.Repositories))
+	for name := range manifest.Repositories {
+		if name != manifest.PrimaryRepositoryName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{manifest.PrimaryRepositoryName}, names...)
+}
+
+// checkTargetDirs makes sure that repositories are pulled into different
+// directories of the target path, otherwise they would be cloned into the
+// same directory concurrently
+func checkTargetDirs(manifest *config.Manifest, names []string) error {
+	dirs := make(map[string][]string, len(names))
+	for _, name := range names {
+		dir := util.GitDirNameFromURL(manifest.Repositories[name].URL())
+		if dir != "" {
+			dirs[dir] = append(dirs[dir], name)
+		}
+	}
+	for _, name := range names {
+		dir := util.GitDirNameFromURL(manifest.Repositories[name].URL())
+		if len(dirs[dir]) > 1 {
+			return ErrDuplicateTargetDir{Dir: dir, Repositories: dirs[dir]}
+		}
+	}
+	return nil
+}
+
+// pullRepository clones the repository or fetches it if it is cloned
+// already, checks it out and returns the hash of the commit it is checked
+// out at. Repositories having the commit to check out are not fetched
+func pullRepository(basePath string, repoConfig *config.Repository) (string, error) {
+	repository, err := repo.NewRepository(basePath, repoConfig)
+	if err != nil {
+		return "", err
+	}
+	defer repository.Driver.Close()
+
+	force := repoConfig.ToCheckoutOptions(true).Force
+	err = repository.Open()
+	switch {
+	case err == git.ErrRepositoryNotExists:
+		err = repository.Download(force)
+	case err != nil:
+	case hasCommit(repository, repoConfig):
+		log.Debugf("Repository %s has the commit to check out, skipping fetch", repository.Name)
+		err = repository.Checkout(force)
+	default:
+		err = repository.Update(force)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repository.Driver.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
+// hasCommit tells if the repository is configured to check out a commit
+// which it already has
+func hasCommit(repository *repo.Repository, repoConfig *config.Repository) bool {
+	if repoConfig.CheckoutOptions == nil || repoConfig.CheckoutOptions.CommitHash == "" {
+		return false
+	}
+	_, err := repository.Driver.CommitObject(plumbing.NewHash(repoConfig.CheckoutOptions.CommitHash))
+	return err == nil
+}
+
+// lockedRepository returns a copy of the repository configuration checking
+// out the commit, other checkout options are kept so that the remote ref is
+// fetched and single branch clones clone the branch or tag of the commit
+func lockedRepository(repoConfig *config.Repository, commit string) *config.Repository {
+	locked := *repoConfig
+	checkout := config.RepoCheckout{}
+	if repoConfig.CheckoutOptions != nil {
+		checkout = *repoConfig.CheckoutOptions
+	}
+	checkout.CommitHash = commit
+	locked.CheckoutOptions = &checkout
+	return &locked
+}
+
+// progressWriter serializes progress lines of concurrent pulls
+type progressWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (p *progressWriter) printf(format string, a ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.out, format, a...)
+}
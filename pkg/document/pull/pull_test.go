@@ -1,13 +1,21 @@
 package pull
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
+	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	fixtures "gopkg.in/src-d/go-git-fixtures.v3"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 
+	"opendev.org/airship/airshipctl/pkg/document/lock"
 	repo2 "opendev.org/airship/airshipctl/pkg/document/repo"
 
 	"github.com/stretchr/testify/assert"
@@ -62,7 +70,7 @@ func TestPull(t *testing.T) {
 		_, err = repo2.NewRepository(".", currentManifest.Repositories[currentManifest.PrimaryRepositoryName])
 		require.NoError(err)
 
-		err = dummyPullSettings.cloneRepositories()
+		err = dummyPullSettings.cloneRepositories(ioutil.Discard)
 
 		require.NoError(err)
 		dummyRepoDirName := util.GitDirNameFromURL(dummyGitDir)
@@ -103,7 +111,7 @@ func TestPull(t *testing.T) {
 		mfst.TargetPath = tmpDir
 		require.NoError(err)
 
-		err = dummyPullSettings.Pull()
+		err = dummyPullSettings.Pull(ioutil.Discard)
 		require.NoError(err)
 
 		dummyRepoDirName := util.GitDirNameFromURL(dummyGitDir)
@@ -112,6 +120,198 @@ func TestPull(t *testing.T) {
 		contents, err := ioutil.ReadFile(path.Join(tmpDir, dummyRepoDirName, ".git/HEAD"))
 		require.NoError(err)
 		assert.Equal("ref: refs/heads/master", strings.TrimRight(string(contents), "\t \n"))
+
+		l, err := lock.Read(path.Join(tmpDir, lock.FileName))
+		require.NoError(err)
+		assert.Equal(&lock.Entry{URL: dummyGitDir, Checkout: "branch/master", Commit: fx.Head.String()},
+			l.Repositories[mfst.PrimaryRepositoryName])
+	})
+
+	t.Run("PullLocked", func(t *testing.T) {
+		dummyPullSettings := getDummyPullSettings()
+		mfst, err := dummyPullSettings.Config().CurrentContextManifest()
+		require.NoError(err)
+
+		err = fixtures.Init()
+		require.NoError(err)
+		fx := fixtures.Basic().One()
+
+		dummyGitDir := fx.DotGit().Root()
+		primary := &config.Repository{
+			URLString: dummyGitDir,
+			CheckoutOptions: &config.RepoCheckout{
+				Branch: "master",
+			},
+		}
+		mfst.Repositories = map[string]*config.Repository{mfst.PrimaryRepositoryName: primary}
+
+		tmpDir, cleanup := testutil.TempDir(t, "airshipctlPullTest-")
+		defer cleanup(t)
+		mfst.TargetPath = tmpDir
+
+		// commit of refs/heads/branch of the fixture
+		lockedCommit := "e8d3ffab552895c19b9fcf7aa264d277cde33881"
+		l := lock.New()
+		l.Set(mfst.PrimaryRepositoryName, primary, lockedCommit)
+		require.NoError(l.Write(lock.Path(mfst)))
+
+		headCommit := func() string {
+			repository, err := repo2.NewRepository(tmpDir, primary)
+			require.NoError(err)
+			require.NoError(repository.Open())
+			defer repository.Driver.Close()
+			head, err := repository.Driver.Head()
+			require.NoError(err)
+			return head.Hash().String()
+		}
+		lockedCommitOf := func() string {
+			l, err := lock.Read(lock.Path(mfst))
+			require.NoError(err)
+			commit, locked := l.Commit(mfst.PrimaryRepositoryName, primary)
+			require.True(locked)
+			return commit
+		}
+
+		require.NoError(dummyPullSettings.Pull(ioutil.Discard))
+		assert.Equal(lockedCommit, headCommit())
+		assert.Equal(lockedCommit, lockedCommitOf())
+
+		dummyPullSettings.Update = true
+		require.NoError(dummyPullSettings.Pull(ioutil.Discard))
+		assert.Equal(fx.Head.String(), headCommit())
+		assert.Equal(fx.Head.String(), lockedCommitOf())
+	})
+
+	t.Run("PullAdvancedUpstream", func(t *testing.T) {
+		dummyPullSettings := getDummyPullSettings()
+		mfst, err := dummyPullSettings.Config().CurrentContextManifest()
+		require.NoError(err)
+
+		upstreamDir, cleanupUpstream := testutil.TempDir(t, "airshipctlPullUpstream-")
+		defer cleanupUpstream(t)
+		upstream, err := git.PlainInit(upstreamDir, false)
+		require.NoError(err)
+		commitUpstream := func(content string) string {
+			require.NoError(ioutil.WriteFile(filepath.Join(upstreamDir, "README"), []byte(content), 0600))
+			tree, err := upstream.Worktree()
+			require.NoError(err)
+			_, err = tree.Add("README")
+			require.NoError(err)
+			hash, err := tree.Commit(content, &git.CommitOptions{
+				Author: &object.Signature{Name: "airship", Email: "airship@example.com", When: time.Now()},
+			})
+			require.NoError(err)
+			return hash.String()
+		}
+
+		primary := &config.Repository{
+			URLString:       upstreamDir,
+			CheckoutOptions: &config.RepoCheckout{Branch: "master"},
+		}
+		mfst.Repositories = map[string]*config.Repository{mfst.PrimaryRepositoryName: primary}
+		tmpDir, cleanup := testutil.TempDir(t, "airshipctlPullTest-")
+		defer cleanup(t)
+		mfst.TargetPath = tmpDir
+		readme := filepath.Join(tmpDir, util.GitDirNameFromURL(upstreamDir), "README")
+
+		first := commitUpstream("first")
+		require.NoError(dummyPullSettings.Pull(ioutil.Discard))
+		contents, err := ioutil.ReadFile(readme)
+		require.NoError(err)
+		assert.Equal("first", string(contents))
+
+		// updates check out the branch at the commit fetched from upstream
+		second := commitUpstream("second")
+		require.NotEqual(first, second)
+		dummyPullSettings.Update = true
+		out := &bytes.Buffer{}
+		require.NoError(dummyPullSettings.Pull(out))
+		assert.Contains(out.String(), "checked out at "+second)
+		contents, err = ioutil.ReadFile(readme)
+		require.NoError(err)
+		assert.Equal("second", string(contents))
+
+		l, err := lock.Read(lock.Path(mfst))
+		require.NoError(err)
+		commit, locked := l.Commit(mfst.PrimaryRepositoryName, primary)
+		assert.True(locked)
+		assert.Equal(second, commit)
+	})
+
+	t.Run("PullConcurrently", func(t *testing.T) {
+		dummyPullSettings := getDummyPullSettings()
+		mfst, err := dummyPullSettings.Config().CurrentContextManifest()
+		require.NoError(err)
+
+		err = fixtures.Init()
+		require.NoError(err)
+		fx := fixtures.Basic().One()
+
+		mfst.Repositories = map[string]*config.Repository{
+			"broken": {URLString: "/nonexistent/broken.git"},
+		}
+		for i := 0; i < 3; i++ {
+			// every copy of the fixture has its own url
+			name := fmt.Sprintf("repo%d", i)
+			mfst.Repositories[name] = &config.Repository{URLString: fx.DotGit().Root()}
+		}
+		mfst.PrimaryRepositoryName = "repo0"
+
+		tmpDir, cleanup := testutil.TempDir(t, "airshipctlPullTest-")
+		defer cleanup(t)
+		mfst.TargetPath = tmpDir
+
+		dummyPullSettings.Jobs = 2
+		out := &bytes.Buffer{}
+		err = dummyPullSettings.Pull(out)
+		require.IsType(ErrPullFailed{}, err)
+		failed := err.(ErrPullFailed).Errors
+		assert.Len(failed, 1)
+		assert.Contains(failed, "broken")
+
+		l, err := lock.Read(lock.Path(mfst))
+		require.NoError(err)
+		for i := 0; i < 3; i++ {
+			name := fmt.Sprintf("repo%d", i)
+			assert.Contains(out.String(), fmt.Sprintf("%s: checked out at %s\n", name, fx.Head))
+			commit, locked := l.Commit(name, mfst.Repositories[name])
+			assert.True(locked)
+			assert.Equal(fx.Head.String(), commit)
+		}
+		assert.NotContains(l.Repositories, "broken")
+	})
+
+	t.Run("DuplicateTargetDirs", func(t *testing.T) {
+		dummyPullSettings := getDummyPullSettings()
+		mfst, err := dummyPullSettings.Config().CurrentContextManifest()
+		require.NoError(err)
+
+		mfst.Repositories = map[string]*config.Repository{
+			mfst.PrimaryRepositoryName: {URLString: "https://opendev.org/airship/treasuremap.git"},
+			"fork":                     {URLString: "https://github.com/example/treasuremap"},
+			"other":                    {URLString: "https://opendev.org/airship/airshipctl.git"},
+		}
+		tmpDir, cleanup := testutil.TempDir(t, "airshipctlPullTest-")
+		defer cleanup(t)
+		mfst.TargetPath = tmpDir
+
+		err = dummyPullSettings.Pull(ioutil.Discard)
+		assert.Equal(ErrDuplicateTargetDir{
+			Dir:          "treasuremap",
+			Repositories: []string{mfst.PrimaryRepositoryName, "fork"},
+		}, err)
+		_, err = os.Stat(lock.Path(mfst))
+		assert.True(os.IsNotExist(err))
+	})
+
+	t.Run("MissingPrimaryRepository", func(t *testing.T) {
+		dummyPullSettings := getDummyPullSettings()
+		mfst, err := dummyPullSettings.Config().CurrentContextManifest()
+		require.NoError(err)
+		mfst.PrimaryRepositoryName = "missing"
+
+		err = dummyPullSettings.Pull(ioutil.Discard)
+		assert.Equal(config.ErrMissingPrimaryRepo{}, err)
 	})
 
 	testutil.CleanUpGitFixtures(t)
@@ -0,0 +1,195 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/filter"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// Render builds the document bundle for the cluster type and phase of the
+// current context, applies the filters and writes resulting documents in the
+// requested output format either to out or to OutputDir, one file per document
+func (s *Settings) Render(out io.Writer) error {
+	conf := s.Config()
+	if err := conf.EnsureComplete(); err != nil {
+		return err
+	}
+
+	entryPoint, err := conf.CurrentContextEntryPoint(s.ClusterType, s.Phase)
+	if err != nil {
+		return err
+	}
+
+	format := s.Output
+	if format == "" {
+		format = document.YAMLFormat
+	}
+	encoder, err := document.GetEncoder(format)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := document.NewBundleByPath(entryPoint)
+	if err != nil {
+		return err
+	}
+
+	docs, err := s.Filter(bundle)
+	if err != nil {
+		return err
+	}
+	if s.ShowOrigin {
+		annotateOrigins(docs)
+	}
+
+	if s.OutputDir != "" {
+		return writeDocuments(s.OutputDir, format, encoder, docs)
+	}
+	return encoder.Encode(out, docs)
+}
+
+// Filter returns documents from the bundle matching every label and
+// annotation filter, any of the api version and kind filters and the
+// logical expression if one is set
+func (s *Settings) Filter(bundle document.Bundle) ([]document.Document, error) {
+	selected, err := s.selectAPIVersions(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []document.Document
+	for _, selector := range s.Selectors() {
+		var found []document.Document
+		found, err = selected.Select(selector)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, found...)
+	}
+
+	if s.RawFilter == "" {
+		return docs, nil
+	}
+	expr, err := filter.Parse(s.RawFilter)
+	if err != nil {
+		return nil, err
+	}
+	return expr.Filter(bundle, docs)
+}
+
+// Selectors converts filter flags into a list of document selectors. Label and
+// annotation filters are joined together, while every kind produces a separate
+// selector. API versions aren't part of the selectors, since an empty group
+// would match documents of any group, e.g. v1 would match apps/v1
+func (s *Settings) Selectors() []document.Selector {
+	base := document.NewSelector()
+	for _, label := range s.Label {
+		base = base.ByLabel(label)
+	}
+	for _, annotation := range s.Annotation {
+		base = base.ByAnnotation(annotation)
+	}
+
+	if len(s.Kind) == 0 {
+		return []document.Selector{base}
+	}
+	kinds := unique(s.Kind)
+	selectors := make([]document.Selector, 0, len(kinds))
+	for _, kind := range kinds {
+		selectors = append(selectors, base.ByKind(kind))
+	}
+	return selectors
+}
+
+// selectAPIVersions returns the documents of the bundle whose apiVersion is
+// equal to any of the api version filters, the same way the apiVersion
+// predicate of a filter expression compares it
+func (s *Settings) selectAPIVersions(bundle document.Bundle) (document.Bundle, error) {
+	if len(s.GroupVersion) == 0 {
+		return bundle, nil
+	}
+
+	apiVersions := make(map[string]bool, len(s.GroupVersion))
+	for _, raw := range s.GroupVersion {
+		gv, err := schema.ParseGroupVersion(raw)
+		if err != nil {
+			return nil, err
+		}
+		apiVersions[gv.String()] = true
+	}
+	return bundle.SelectByFieldValue("apiVersion", func(v interface{}) bool {
+		return apiVersions[fmt.Sprint(v)]
+	})
+}
+
+// annotateOrigins sets origins of documents loaded from files to OriginAnnotation
+func annotateOrigins(docs []document.Document) {
+	for _, doc := range docs {
+		if origin := doc.Origin(); origin != nil {
+			doc.Annotate(document.OriginAnnotation, origin.String())
+		}
+	}
+}
+
+// fileExtensions maps output format to the extension of files written to output directory
+var fileExtensions = map[string]string{
+	document.YAMLFormat:      "yaml",
+	document.JSONFormat:      "json",
+	document.JSONLinesFormat: "json",
+	document.TableFormat:     "txt",
+}
+
+// writeDocuments encodes every document into a separate file within dir
+func writeDocuments(dir string, format string, encoder document.Encoder, docs []document.Document) error {
+	extension, found := fileExtensions[format]
+	if !found {
+		extension = format
+	}
+
+	files := make(map[string][]byte, len(docs))
+	for _, doc := range docs {
+		buf := &bytes.Buffer{}
+		if err := encoder.Encode(buf, []document.Document{doc}); err != nil {
+			return err
+		}
+		files[filepath.Join(dir, documentFileName(doc, extension))] = buf.Bytes()
+	}
+
+	fs := document.NewDocumentFs()
+	if err := fs.MkdirAll(dir); err != nil {
+		return err
+	}
+	return util.WriteFiles(files, 0600)
+}
+
+// documentFileName returns file name in the form of <kind>_<namespace>_<name>.<extension>,
+// namespace is omitted for cluster scoped documents
+func documentFileName(doc document.Document, extension string) string {
+	parts := []string{strings.ToLower(doc.GetKind())}
+	if ns := doc.GetNamespace(); ns != "" {
+		parts = append(parts, ns)
+	}
+	parts = append(parts, doc.GetName())
+	return fmt.Sprintf("%s.%s", strings.Join(parts, "_"), extension)
+}
+
+func unique(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,208 @@
+package render_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/render"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const (
+	airshipConfigFile = "testdata/config.yaml"
+	kubeconfigPath    = "testdata/kubeconfig.yaml"
+)
+
+func getDummyRenderSettings(t *testing.T) *render.Settings {
+	t.Helper()
+	rs := &environment.AirshipCTLSettings{}
+
+	acp, err := filepath.Abs(airshipConfigFile)
+	require.NoError(t, err)
+	kcp, err := filepath.Abs(kubeconfigPath)
+	require.NoError(t, err)
+
+	rs.SetAirshipConfigPath(acp)
+	rs.SetKubeConfigPath(kcp)
+	rs.InitConfig()
+
+	return &render.Settings{
+		AirshipCTLSettings: rs,
+		ClusterType:        "ephemeral",
+		Phase:              "initinfra",
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name       string
+		setFilters func(*render.Settings)
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:       "no filters",
+			setFilters: func(*render.Settings) {},
+			expected:   []string{"name: test-secret", "name: test-deployment", "name: test\n"},
+		},
+		{
+			name:       "filter by kind",
+			setFilters: func(s *render.Settings) { s.Kind = []string{"Secret"} },
+			expected:   []string{"name: test-secret"},
+			unexpected: []string{"name: test-deployment", "kind: Namespace"},
+		},
+		{
+			name:       "filter by multiple kinds",
+			setFilters: func(s *render.Settings) { s.Kind = []string{"Secret", "Namespace", "Secret"} },
+			expected:   []string{"name: test-secret", "kind: Namespace"},
+			unexpected: []string{"name: test-deployment"},
+		},
+		{
+			name:       "filter by api version",
+			setFilters: func(s *render.Settings) { s.GroupVersion = []string{"apps/v1"} },
+			expected:   []string{"name: test-deployment"},
+			unexpected: []string{"name: test-secret", "kind: Namespace"},
+		},
+		{
+			name:       "filter by core api version",
+			setFilters: func(s *render.Settings) { s.GroupVersion = []string{"v1"} },
+			expected:   []string{"name: test-secret", "kind: Namespace"},
+			unexpected: []string{"name: test-deployment"},
+		},
+		{
+			name: "filter by api version and kind",
+			setFilters: func(s *render.Settings) {
+				s.GroupVersion = []string{"v1"}
+				s.Kind = []string{"Deployment", "Secret"}
+			},
+			expected:   []string{"name: test-secret"},
+			unexpected: []string{"name: test-deployment", "kind: Namespace"},
+		},
+		{
+			name:       "filter by label",
+			setFilters: func(s *render.Settings) { s.Label = []string{"airshipit.org/phase=initinfra"} },
+			expected:   []string{"name: test-secret", "name: test-deployment"},
+			unexpected: []string{"kind: Namespace"},
+		},
+		{
+			name: "filter by label and annotation",
+			setFilters: func(s *render.Settings) {
+				s.Label = []string{"airshipit.org/phase=initinfra"}
+				s.Annotation = []string{"airshipit.org/component=dashboard"}
+			},
+			expected:   []string{"name: test-deployment"},
+			unexpected: []string{"name: test-secret", "kind: Namespace"},
+		},
+		{
+			name: "filter by expression",
+			setFilters: func(s *render.Settings) {
+				s.RawFilter = "kind=Namespace or (label(airshipit.org/phase=initinfra) and not kind=Deployment)"
+			},
+			expected:   []string{"name: test-secret", "kind: Namespace"},
+			unexpected: []string{"name: test-deployment"},
+		},
+		{
+			name: "filter by flags and expression",
+			setFilters: func(s *render.Settings) {
+				s.Kind = []string{"Secret", "Deployment"}
+				s.RawFilter = "field(spec.replicas)=1"
+			},
+			expected:   []string{"name: test-deployment"},
+			unexpected: []string{"name: test-secret", "kind: Namespace"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			settings := getDummyRenderSettings(t)
+			tt.setFilters(settings)
+
+			out := &bytes.Buffer{}
+			require.NoError(t, settings.Render(out))
+			for _, expected := range tt.expected {
+				assert.Contains(t, out.String(), expected)
+			}
+			for _, unexpected := range tt.unexpected {
+				assert.NotContains(t, out.String(), unexpected)
+			}
+		})
+	}
+}
+
+func TestRenderOutputFormat(t *testing.T) {
+	settings := getDummyRenderSettings(t)
+	settings.Output = "jsonl"
+	settings.Kind = []string{"Secret"}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, settings.Render(out))
+	assert.Equal(t, 1, strings.Count(out.String(), "\n"))
+	assert.Contains(t, out.String(), `"name":"test-secret"`)
+
+	settings.Output = "xml"
+	assert.Error(t, settings.Render(&bytes.Buffer{}))
+}
+
+func TestRenderShowOrigin(t *testing.T) {
+	settings := getDummyRenderSettings(t)
+	settings.Kind = []string{"Secret"}
+	settings.ShowOrigin = true
+
+	path, err := filepath.Abs("testdata/primary/site/test-site/ephemeral/initinfra/resources.yaml")
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, settings.Render(out))
+	assert.Contains(t, out.String(), document.OriginAnnotation+":")
+	assert.Contains(t, out.String(), "path: "+path)
+}
+
+func TestRenderOutputDir(t *testing.T) {
+	tmpDir, cleanup := testutil.TempDir(t, "airshipctlRenderTest-")
+	defer cleanup(t)
+
+	settings := getDummyRenderSettings(t)
+	settings.OutputDir = filepath.Join(tmpDir, "rendered")
+
+	out := &bytes.Buffer{}
+	require.NoError(t, settings.Render(out))
+	assert.Empty(t, out.String())
+
+	assert.FileExists(t, filepath.Join(settings.OutputDir, "namespace_test.yaml"))
+	assert.FileExists(t, filepath.Join(settings.OutputDir, "secret_test_test-secret.yaml"))
+	assert.FileExists(t, filepath.Join(settings.OutputDir, "deployment_test_test-deployment.yaml"))
+
+	settings.Output = "json"
+	require.NoError(t, settings.Render(out))
+	assert.FileExists(t, filepath.Join(settings.OutputDir, "namespace_test.json"))
+}
+
+func TestRenderErrors(t *testing.T) {
+	t.Run("invalid cluster type", func(t *testing.T) {
+		settings := getDummyRenderSettings(t)
+		settings.ClusterType = "unknown"
+		assert.Error(t, settings.Render(&bytes.Buffer{}))
+	})
+
+	t.Run("invalid api version", func(t *testing.T) {
+		settings := getDummyRenderSettings(t)
+		settings.GroupVersion = []string{"a/b/c"}
+		err := settings.Render(&bytes.Buffer{})
+		require.Error(t, err)
+		assert.True(t, strings.Contains(err.Error(), "a/b/c"))
+	})
+
+	t.Run("invalid filter expression", func(t *testing.T) {
+		settings := getDummyRenderSettings(t)
+		settings.RawFilter = "kind=Secret and"
+		assert.Error(t, settings.Render(&bytes.Buffer{}))
+	})
+}
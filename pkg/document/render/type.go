@@ -17,4 +17,15 @@ type Settings struct {
 	Kind []string
 	// RawFilter contains logical expression to filter documents
 	RawFilter string
+	// ClusterType is the cluster type whose entrypoint is to be rendered
+	ClusterType string
+	// Phase is the phase within the cluster type to be rendered
+	Phase string
+	// OutputDir is a directory to write one file per rendered document,
+	// documents are written to the output stream if it is empty
+	OutputDir string
+	// Output is a format documents are written in, e.g. yaml, json, jsonl or table
+	Output string
+	// ShowOrigin annotates documents with their origins
+	ShowOrigin bool
 }
@@ -1,10 +1,18 @@
 package repo
 
 import (
+	"io/ioutil"
+	"os"
+
 	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/osfs"
 	"gopkg.in/src-d/go-git.v4"
+	gitconfig "gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/cache"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"gopkg.in/src-d/go-git.v4/storage"
+	"gopkg.in/src-d/go-git.v4/storage/filesystem"
 )
 
 // Adapter is abstraction to SVC
@@ -12,9 +20,14 @@ type Adapter interface {
 	Open() error
 	Clone(co *git.CloneOptions) error
 	Fetch(fo *git.FetchOptions) error
+	Unshallow(fo *git.FetchOptions) error
 	Worktree() (*git.Worktree, error)
 	Head() (*plumbing.Reference, error)
+	Reference(name plumbing.ReferenceName, resolved bool) (*plumbing.Reference, error)
+	SetReference(ref *plumbing.Reference) error
 	ResolveRevision(plumbing.Revision) (*plumbing.Hash, error)
+	CommitObject(plumbing.Hash) (*object.Commit, error)
+	SparseCheckout(co *git.CheckoutOptions, paths []string) error
 	IsOpen() bool
 	SetFilesystem(billy.Filesystem)
 	SetStorer(s storage.Storer)
@@ -64,6 +77,62 @@ func (g *GitDriver) Clone(co *git.CloneOptions) error {
 	return nil
 }
 
+// Unshallow fetches the whole history of refs of the default remote and refs
+// of fetch options if the repository is shallow. Fetches never deepen shallow
+// repositories since they skip refs whose commits exist already, so history
+// is fetched into an empty storage and missing objects are copied from it
+func (g *GitDriver) Unshallow(fo *git.FetchOptions) error {
+	shallows, err := g.Repository.Storer.Shallow()
+	if err != nil || len(shallows) == 0 {
+		return err
+	}
+	remote, err := g.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := ioutil.TempDir("", "airship-history-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	history := filesystem.NewStorage(osfs.New(tmpDir), cache.NewObjectLRUDefault())
+
+	historyOptions := *fo
+	historyOptions.Depth = 0
+	historyOptions.RefSpecs = append(append([]gitconfig.RefSpec{}, remote.Config().Fetch...), fo.RefSpecs...)
+	err = git.NewRemote(history, remote.Config()).Fetch(&historyOptions)
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return err
+	}
+
+	objects, err := history.IterEncodedObjects(plumbing.AnyObject)
+	if err != nil {
+		return err
+	}
+	err = objects.ForEach(func(obj plumbing.EncodedObject) error {
+		if g.Repository.Storer.HasEncodedObject(obj.Hash()) == nil {
+			return nil
+		}
+		_, setErr := g.Repository.Storer.SetEncodedObject(obj)
+		return setErr
+	})
+	if err != nil {
+		return err
+	}
+	// git treats repositories having the shallow file as shallow even if
+	// the file is empty
+	if s, ok := g.Repository.Storer.(*filesystem.Storage); ok {
+		return s.Filesystem().Remove("shallow")
+	}
+	return g.Repository.Storer.SetShallow(nil)
+}
+
+// SetReference implements repository interface
+func (g *GitDriver) SetReference(ref *plumbing.Reference) error {
+	return g.Repository.Storer.SetReference(ref)
+}
+
 func (g *GitDriver) SetFilesystem(fs billy.Filesystem) {
 	g.Filesystem = fs
 }
@@ -3,12 +3,16 @@ package repo
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	"gopkg.in/src-d/go-billy.v4"
 	"gopkg.in/src-d/go-billy.v4/osfs"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
 	"gopkg.in/src-d/go-git.v4/plumbing/cache"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport"
 	"gopkg.in/src-d/go-git.v4/storage"
 	"gopkg.in/src-d/go-git.v4/storage/filesystem"
@@ -22,11 +26,23 @@ var (
 	ErrCantParseURL = errors.New("could not get target directory from url")
 )
 
+// ErrCommitNotFound returned if the commit to check out is not found in the
+// repository even after fetching its whole history
+type ErrCommitNotFound struct {
+	Repository string
+	Commit     string
+}
+
+func (e ErrCommitNotFound) Error() string {
+	return fmt.Sprintf("commit %s is not found in repository %s", e.Commit, e.Repository)
+}
+
 type OptionsBuilder interface {
 	ToAuth() (transport.AuthMethod, error)
 	ToCloneOptions(auth transport.AuthMethod) *git.CloneOptions
 	ToCheckoutOptions(force bool) *git.CheckoutOptions
 	ToFetchOptions(auth transport.AuthMethod) *git.FetchOptions
+	ToSparsePaths() []string
 	URL() string
 }
 
@@ -82,16 +98,47 @@ func (repo *Repository) Update(force bool) error {
 	if err != nil && err != git.NoErrAlreadyUpToDate {
 		return fmt.Errorf("failed to fetch refs for repository %v: %w", repo.Name, err)
 	}
+	if err = repo.fetchCommit(); err != nil {
+		return err
+	}
+	if co := repo.ToCheckoutOptions(force); co != nil {
+		if err = repo.updateBranch(co.Branch); err != nil {
+			return fmt.Errorf("failed to update branch %s of repository %v: %w", co.Branch.Short(), repo.Name, err)
+		}
+	}
 	return repo.Checkout(force)
 }
 
+// updateBranch points the local branch at the commit of its remote-tracking
+// branch, since fetches only move remote-tracking branches. Branches without
+// remote-tracking branches are left as is
+func (repo *Repository) updateBranch(branch plumbing.ReferenceName) error {
+	if !branch.IsBranch() {
+		return nil
+	}
+	remote, err := repo.Driver.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, branch.Short()), true)
+	if err == plumbing.ErrReferenceNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	log.Debugf("Moving branch %s of the repository %s to %s", branch.Short(), repo.Name, remote.Hash())
+	return repo.Driver.SetReference(plumbing.NewHashReference(branch, remote.Hash()))
+}
+
 // Checkout git repository, ToCheckoutOptions method will be used go get CheckoutOptions
+// only files under ToSparsePaths are checked out if there are any
 func (repo *Repository) Checkout(enforce bool) error {
 	log.Debugf("Attempting to checkout the repository %s", repo.Name)
 	if !repo.Driver.IsOpen() {
 		return ErrNoOpenRepo
 	}
 	co := repo.ToCheckoutOptions(enforce)
+	if paths := repo.ToSparsePaths(); len(paths) > 0 {
+		log.Debugf("Checking out %v of the repository %s", paths, repo.Name)
+		return repo.Driver.SparseCheckout(co, paths)
+	}
 	tree, err := repo.Driver.Worktree()
 	if err != nil {
 		return fmt.Errorf("could not get worktree from the repo, %w", err)
@@ -99,6 +146,23 @@ func (repo *Repository) Checkout(enforce bool) error {
 	return tree.Checkout(co)
 }
 
+// IsClean tells if the working tree and the index have no changes, only
+// files under ToSparsePaths are checked if there are any
+func (repo *Repository) IsClean() (bool, error) {
+	if !repo.Driver.IsOpen() {
+		return false, ErrNoOpenRepo
+	}
+	tree, err := repo.Driver.Worktree()
+	if err != nil {
+		return false, err
+	}
+	status, err := tree.Status()
+	if err != nil {
+		return false, err
+	}
+	return StatusClean(status, repo.ToSparsePaths()), nil
+}
+
 // Open the repository
 func (repo *Repository) Open() error {
 	log.Debugf("Attempting to open repository %s", repo.Name)
@@ -118,7 +182,8 @@ func (repo *Repository) Clone() error {
 
 // Download will clone and checkout repository based on auth and checkout fields of the Repository object
 // If repository is already cloned, it will be opened and checked out to configured hash,branch,tag etc...
-// no remotes will be modified in this case, also no refs will be updated.
+// no remotes will be modified in this case, also no refs will be updated except refs fetched
+// explicitly by the fetch options, e.g. the remote ref of gerrit change or github pull request.
 // enforce parameter is used to simulate git reset --hard option.
 // If you want to enforce state of the repository, please delete current git repository before downloading.
 func (repo *Repository) Download(enforceCheckout bool) error {
@@ -136,5 +201,92 @@ func (repo *Repository) Download(enforceCheckout bool) error {
 		}
 	}
 
+	if err := repo.fetchRefSpecs(); err != nil {
+		return err
+	}
+	if err := repo.fetchCommit(); err != nil {
+		return err
+	}
 	return repo.Checkout(enforceCheckout)
 }
+
+// fetchRefSpecs fetches refs set explicitly in fetch options, refs fetched by
+// clone are left as is
+func (repo *Repository) fetchRefSpecs() error {
+	auth, err := repo.ToAuth()
+	if err != nil {
+		return err
+	}
+	fo := repo.ToFetchOptions(auth)
+	if fo == nil || len(fo.RefSpecs) == 0 {
+		return nil
+	}
+	log.Debugf("Fetching %v of the repository %s", fo.RefSpecs, repo.Name)
+	err = repo.Driver.Fetch(fo)
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("failed to fetch refs for repository %v: %w", repo.Name, err)
+	}
+	return nil
+}
+
+// fetchCommit fetches the whole history of the repository if it doesn't have
+// the commit to check out, since shallow clones limited by the depth of clone
+// options miss older commits, e.g. commits pinned by the lock file
+func (repo *Repository) fetchCommit() error {
+	co := repo.ToCheckoutOptions(false)
+	if co == nil || co.Hash.IsZero() {
+		return nil
+	}
+	_, err := repo.Driver.CommitObject(co.Hash)
+	if err != plumbing.ErrObjectNotFound {
+		return err
+	}
+	auth, err := repo.ToAuth()
+	if err != nil {
+		return err
+	}
+	fo := repo.ToFetchOptions(auth)
+	if fo == nil {
+		fo = &git.FetchOptions{Auth: auth}
+	}
+	log.Debugf("Fetching the whole history of the repository %s to check out %s", repo.Name, co.Hash)
+	if err = repo.Driver.Unshallow(fo); err != nil {
+		return fmt.Errorf("failed to fetch history of repository %v: %w", repo.Name, err)
+	}
+	if _, err = repo.Driver.CommitObject(co.Hash); err == plumbing.ErrObjectNotFound {
+		return ErrCommitNotFound{Repository: repo.Name, Commit: co.Hash.String()}
+	}
+	return err
+}
+
+// Export writes files of the repository tree at given revision (e.g. HEAD,
+// branch, tag or commit hash) to dst directory without modifying the worktree
+func (repo *Repository) Export(revision string, dst string) error {
+	log.Debugf("Exporting revision %s of the repository %s to %s", revision, repo.Name, dst)
+	if !repo.Driver.IsOpen() {
+		return ErrNoOpenRepo
+	}
+	hash, err := repo.Driver.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return fmt.Errorf("failed to resolve revision %s of repository %v: %w", revision, repo.Name, err)
+	}
+	commit, err := repo.Driver.CommitObject(*hash)
+	if err != nil {
+		return err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+	return tree.Files().ForEach(func(f *object.File) error {
+		contents, err := f.Contents()
+		if err != nil {
+			return err
+		}
+		fileName := filepath.Join(dst, filepath.FromSlash(f.Name))
+		if err = os.MkdirAll(filepath.Dir(fileName), 0750); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(fileName, []byte(contents), 0600)
+	})
+}
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,6 +9,7 @@ import (
 	"gopkg.in/src-d/go-billy.v4/memfs"
 	fixtures "gopkg.in/src-d/go-git-fixtures.v3"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/plumbing"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport"
 	"gopkg.in/src-d/go-git.v4/storage/memory"
@@ -21,6 +23,7 @@ type mockBuilder struct {
 	CheckoutOptions *git.CheckoutOptions
 	FetchOptions    *git.FetchOptions
 	URLString       string
+	SparsePaths     []string
 	AuthError       error
 }
 
@@ -36,7 +39,8 @@ func (md mockBuilder) ToCheckoutOptions(bool) *git.CheckoutOptions {
 func (md mockBuilder) ToFetchOptions(transport.AuthMethod) *git.FetchOptions {
 	return md.FetchOptions
 }
-func (md mockBuilder) URL() string { return md.URLString }
+func (md mockBuilder) ToSparsePaths() []string { return md.SparsePaths }
+func (md mockBuilder) URL() string             { return md.URLString }
 
 func TestDownload(t *testing.T) {
 	err := fixtures.Init()
@@ -77,6 +81,42 @@ func TestDownload(t *testing.T) {
 	assert.NotNil(t, ref.String())
 }
 
+func TestDownloadRemoteRef(t *testing.T) {
+	err := fixtures.Init()
+	require.NoError(t, err)
+	defer testutil.CleanUpGitFixtures(t)
+
+	fx := fixtures.Basic().One()
+	fetchHead := plumbing.ReferenceName("FETCH_HEAD")
+	builder := &mockBuilder{
+		CheckoutOptions: &git.CheckoutOptions{Branch: fetchHead},
+		CloneOptions:    &git.CloneOptions{URL: fx.DotGit().Root()},
+		FetchOptions: &git.FetchOptions{
+			RefSpecs: []config.RefSpec{"+refs/heads/branch:" + config.RefSpec(fetchHead)},
+		},
+		URLString: fx.DotGit().Root(),
+	}
+
+	repo, err := NewRepository(".", builder)
+	require.NoError(t, err)
+	repo.Driver.SetFilesystem(memfs.New())
+	repo.Driver.SetStorer(memory.NewStorage())
+
+	require.NoError(t, repo.Download(false))
+
+	fetched, err := repo.Driver.ResolveRevision(plumbing.Revision(fetchHead))
+	require.NoError(t, err)
+	master, err := repo.Driver.ResolveRevision("refs/remotes/origin/master")
+	require.NoError(t, err)
+	require.NotEqual(t, master.String(), fetched.String())
+
+	// HEAD is detached at the fetched commit
+	ref, err := repo.Driver.Head()
+	require.NoError(t, err)
+	assert.Equal(t, plumbing.HEAD, ref.Name())
+	assert.Equal(t, fetched.String(), ref.Hash().String())
+}
+
 func TestUpdate(t *testing.T) {
 	err := fixtures.Init()
 	require.NoError(t, err)
@@ -141,6 +181,52 @@ func TestUpdate(t *testing.T) {
 	assert.Error(t, updateError)
 }
 
+func TestDownloadShallow(t *testing.T) {
+	err := fixtures.Init()
+	require.NoError(t, err)
+	defer testutil.CleanUpGitFixtures(t)
+
+	fx := fixtures.Basic().One()
+	url := fx.DotGit().Root()
+	builder := &mockBuilder{
+		CheckoutOptions: &git.CheckoutOptions{Branch: plumbing.Master},
+		CloneOptions:    &git.CloneOptions{URL: url},
+		FetchOptions:    &git.FetchOptions{},
+		URLString:       url,
+	}
+
+	storer := memory.NewStorage()
+	repo, err := NewRepository(".", builder)
+	require.NoError(t, err)
+	repo.Driver = &GitDriver{Filesystem: memfs.New(), Storer: storer}
+	require.NoError(t, repo.Download(false))
+
+	// the clone is made shallow at HEAD so that the previous commit is missing
+	head, err := repo.Driver.Head()
+	require.NoError(t, err)
+	prevCommitHash, err := repo.Driver.ResolveRevision("HEAD~1")
+	require.NoError(t, err)
+	require.NoError(t, storer.SetShallow([]plumbing.Hash{head.Hash()}))
+	delete(storer.ObjectStorage.Objects, *prevCommitHash)
+	delete(storer.ObjectStorage.Commits, *prevCommitHash)
+	_, err = repo.Driver.CommitObject(*prevCommitHash)
+	require.Equal(t, plumbing.ErrObjectNotFound, err)
+
+	// the whole history is fetched to check out the missing commit
+	builder.CheckoutOptions = &git.CheckoutOptions{Hash: *prevCommitHash}
+	require.NoError(t, repo.Download(false))
+	head, err = repo.Driver.Head()
+	require.NoError(t, err)
+	assert.Equal(t, prevCommitHash.String(), head.Hash().String())
+	shallows, err := storer.Shallow()
+	require.NoError(t, err)
+	assert.Empty(t, shallows)
+
+	missing := plumbing.NewHash("0123456789012345678901234567890123456789")
+	builder.CheckoutOptions = &git.CheckoutOptions{Hash: missing}
+	assert.Equal(t, ErrCommitNotFound{Repository: repo.Name, Commit: missing.String()}, repo.Download(false))
+}
+
 func TestOpen(t *testing.T) {
 	err := fixtures.Init()
 	require.NoError(t, err)
@@ -203,3 +289,37 @@ func TestCheckout(t *testing.T) {
 	err = repo.Checkout(true)
 	assert.Error(t, err)
 }
+
+func TestExport(t *testing.T) {
+	err := fixtures.Init()
+	require.NoError(t, err)
+	defer testutil.CleanUpGitFixtures(t)
+
+	fx := fixtures.Basic().One()
+	url := fx.DotGit().Root()
+	builder := &mockBuilder{
+		CheckoutOptions: &git.CheckoutOptions{Branch: plumbing.Master},
+		URLString:       url,
+		CloneOptions:    &git.CloneOptions{Auth: nil, URL: url},
+	}
+
+	repo, err := NewRepository(".", builder)
+	require.NoError(t, err)
+	repo.Driver = &GitDriver{
+		Filesystem: memfs.New(),
+		Storer:     memory.NewStorage(),
+	}
+
+	tmpDir, cleanup := testutil.TempDir(t, "airshipctlExportTest-")
+	defer cleanup(t)
+
+	// repository is not open yet
+	assert.Equal(t, ErrNoOpenRepo, repo.Export("HEAD", tmpDir))
+
+	require.NoError(t, repo.Clone())
+	require.NoError(t, repo.Export("HEAD", tmpDir))
+	assert.FileExists(t, filepath.Join(tmpDir, "go", "example.go"))
+	assert.FileExists(t, filepath.Join(tmpDir, "CHANGELOG"))
+
+	assert.Error(t, repo.Export("non-existent-revision", tmpDir))
+}
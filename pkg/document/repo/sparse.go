@@ -0,0 +1,222 @@
+package repo
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/filemode"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/index"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// SparseCheckout checks out files of the commit under given paths only, files
+// of the previous checkout are removed from the working tree. The index holds
+// the checked out files, so changes outside of the paths are reported by
+// status and should be ignored, see StatusClean
+func (g *GitDriver) SparseCheckout(co *git.CheckoutOptions, paths []string) error {
+	if !g.IsOpen() {
+		return ErrNoOpenRepo
+	}
+	hash, err := g.checkoutCommit(co)
+	if err != nil {
+		return err
+	}
+	commit, err := g.CommitObject(hash)
+	if err != nil {
+		return err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+
+	if !co.Force {
+		unstaged, err := g.hasUnstagedChanges(paths)
+		if err != nil {
+			return err
+		}
+		if unstaged {
+			return git.ErrUnstagedChanges
+		}
+	}
+
+	if err = g.removeIndexedFiles(); err != nil {
+		return err
+	}
+	idx := &index.Index{Version: 2}
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if !InSparsePaths(f.Name, paths) {
+			return nil
+		}
+		entry, err := g.writeFile(f)
+		if err != nil {
+			return err
+		}
+		idx.Entries = append(idx.Entries, entry)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(idx.Entries, func(i, j int) bool { return idx.Entries[i].Name < idx.Entries[j].Name })
+	if err = g.Storer.SetIndex(idx); err != nil {
+		return err
+	}
+
+	head := plumbing.NewHashReference(plumbing.HEAD, hash)
+	if co.Hash.IsZero() && co.Branch.IsBranch() {
+		head = plumbing.NewSymbolicReference(plumbing.HEAD, co.Branch)
+	}
+	return g.Storer.SetReference(head)
+}
+
+// InSparsePaths tells if the file of the repository is under any of the paths,
+// every file is if there are no paths
+func InSparsePaths(name string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, p := range paths {
+		p = strings.Trim(path.Clean(p), "/")
+		if p == "." || name == p || strings.HasPrefix(name, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkoutCommit returns the hash of the commit to check out, tags are
+// peeled to their commits
+func (g *GitDriver) checkoutCommit(co *git.CheckoutOptions) (plumbing.Hash, error) {
+	if !co.Hash.IsZero() {
+		return co.Hash, nil
+	}
+	name := co.Branch
+	if name == "" {
+		name = plumbing.HEAD
+	}
+	ref, err := g.Reference(name, true)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	obj, err := g.Object(plumbing.AnyObject, ref.Hash())
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if tag, ok := obj.(*object.Tag); ok {
+		commit, err := tag.Commit()
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		return commit.Hash, nil
+	}
+	return ref.Hash(), nil
+}
+
+// hasUnstagedChanges tells if tracked files under the paths are changed in
+// the working tree
+func (g *GitDriver) hasUnstagedChanges(paths []string) (bool, error) {
+	w, err := g.Worktree()
+	if err != nil {
+		return false, err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return false, err
+	}
+	for name, fileStatus := range status {
+		if !InSparsePaths(name, paths) {
+			continue
+		}
+		if fileStatus.Worktree != git.Unmodified && fileStatus.Worktree != git.Untracked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// StatusClean tells if the status reports no changes of files under the paths
+func StatusClean(status git.Status, paths []string) bool {
+	for name, fileStatus := range status {
+		if !InSparsePaths(name, paths) {
+			continue
+		}
+		if fileStatus.Staging != git.Unmodified || fileStatus.Worktree != git.Unmodified {
+			return false
+		}
+	}
+	return true
+}
+
+// removeIndexedFiles removes files of the index from the working tree along
+// with directories left empty
+func (g *GitDriver) removeIndexedFiles() error {
+	idx, err := g.Storer.Index()
+	if err != nil {
+		return err
+	}
+	for _, e := range idx.Entries {
+		if err = g.Filesystem.Remove(e.Name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		for dir := path.Dir(e.Name); dir != "."; dir = path.Dir(dir) {
+			if g.Filesystem.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// writeFile writes the file to the working tree and returns its index entry
+func (g *GitDriver) writeFile(f *object.File) (*index.Entry, error) {
+	contents, err := f.Contents()
+	if err != nil {
+		return nil, err
+	}
+	if err = g.Filesystem.MkdirAll(path.Dir(f.Name), 0750); err != nil {
+		return nil, err
+	}
+
+	if f.Mode == filemode.Symlink {
+		err = g.Filesystem.Symlink(contents, f.Name)
+	} else {
+		err = writeRegularFile(g.Filesystem, f.Name, contents, f.Mode == filemode.Executable)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := g.Filesystem.Lstat(f.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &index.Entry{
+		Hash:       f.Hash,
+		Name:       f.Name,
+		Mode:       f.Mode,
+		Size:       uint32(info.Size()),
+		ModifiedAt: info.ModTime(),
+	}, nil
+}
+
+func writeRegularFile(fs billy.Filesystem, name, contents string, executable bool) error {
+	perm := os.FileMode(0644)
+	if executable {
+		perm = 0755
+	}
+	file, err := fs.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write([]byte(contents)); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/util"
+	fixtures "gopkg.in/src-d/go-git-fixtures.v3"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestSparseCheckout(t *testing.T) {
+	err := fixtures.Init()
+	require.NoError(t, err)
+	defer testutil.CleanUpGitFixtures(t)
+
+	fx := fixtures.Basic().One()
+	url := fx.DotGit().Root()
+	builder := &mockBuilder{
+		CheckoutOptions: &git.CheckoutOptions{Branch: plumbing.Master},
+		CloneOptions:    &git.CloneOptions{URL: url, NoCheckout: true},
+		URLString:       url,
+		SparsePaths:     []string{"go", "json/short.json"},
+	}
+
+	fs := memfs.New()
+	repo, err := NewRepository(".", builder)
+	require.NoError(t, err)
+	repo.Driver.SetFilesystem(fs)
+	repo.Driver.SetStorer(memory.NewStorage())
+
+	require.NoError(t, repo.Download(false))
+	for _, name := range []string{"go/example.go", "json/short.json"} {
+		_, err = fs.Stat(name)
+		assert.NoError(t, err, name)
+	}
+	for _, name := range []string{"CHANGELOG", "json/long.json", "php"} {
+		_, err = fs.Stat(name)
+		assert.Error(t, err, name)
+	}
+
+	ref, err := repo.Driver.Head()
+	require.NoError(t, err)
+	assert.Equal(t, fx.Head, ref.Hash())
+	clean, err := repo.IsClean()
+	require.NoError(t, err)
+	assert.True(t, clean)
+
+	// changes under sparse paths are kept unless checkout is forced
+	require.NoError(t, util.WriteFile(fs, "go/example.go", []byte("changed"), 0600))
+	clean, err = repo.IsClean()
+	require.NoError(t, err)
+	assert.False(t, clean)
+	assert.Equal(t, git.ErrUnstagedChanges, repo.Checkout(false))
+	builder.CheckoutOptions = &git.CheckoutOptions{Branch: plumbing.Master, Force: true}
+	require.NoError(t, repo.Checkout(true))
+
+	f, err := fs.Open("go/example.go")
+	require.NoError(t, err)
+	defer f.Close()
+	contents, err := ioutil.ReadAll(f)
+	require.NoError(t, err)
+	assert.NotEqual(t, "changed", string(contents))
+}
+
+func TestInSparsePaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []string
+		expected bool
+	}{
+		{name: "manifests/site/test/kustomization.yaml", expected: true},
+		{name: "manifests/site/test/kustomization.yaml", paths: []string{"manifests/site"}, expected: true},
+		{name: "manifests/site/test/kustomization.yaml", paths: []string{"manifests/site/"}, expected: true},
+		{name: "manifests/site/test/kustomization.yaml", paths: []string{"."}, expected: true},
+		{name: "manifests/function/a.yaml", paths: []string{"manifests/site", "manifests/function"}, expected: true},
+		{name: "manifests/sites/a.yaml", paths: []string{"manifests/site"}, expected: false},
+		{name: "README.md", paths: []string{"manifests"}, expected: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, InSparsePaths(tt.name, tt.paths), "%s in %v", tt.name, tt.paths)
+	}
+}
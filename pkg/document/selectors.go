@@ -118,13 +118,27 @@ func NewBMCCredentialsSelector(name string) Selector {
 // spec.networkData.name and spec.networkData.namespace where to find the secret,
 // if either of these fields are not defined in Document error will be returned
 func NewNetworkDataSelector(bmhDoc Document) (Selector, error) {
+	return newSecretReferenceSelector(bmhDoc, "spec.networkData")
+}
+
+// NewUserDataSelector returns selector that can be used to get secret with
+// user data, bmhDoc argument is a document interface, that should hold fields
+// spec.userData.name and spec.userData.namespace where to find the secret,
+// if either of these fields are not defined in Document error will be returned
+func NewUserDataSelector(bmhDoc Document) (Selector, error) {
+	return newSecretReferenceSelector(bmhDoc, "spec.userData")
+}
+
+// newSecretReferenceSelector returns selector to get secret referenced by
+// the name and namespace fields of the reference located at path
+func newSecretReferenceSelector(doc Document, path string) (Selector, error) {
 	selector := NewSelector()
-	// extract the network data document pointer from the bmh document
-	netConfDocName, err := bmhDoc.GetString("spec.networkData.name")
+	// extract the secret document pointer from the document
+	name, err := doc.GetString(path + ".name")
 	if err != nil {
 		return selector, err
 	}
-	netConfDocNamespace, err := bmhDoc.GetString("spec.networkData.namespace")
+	namespace, err := doc.GetString(path + ".namespace")
 	if err != nil {
 		return selector, err
 	}
@@ -132,8 +146,8 @@ func NewNetworkDataSelector(bmhDoc Document) (Selector, error) {
 	// try and find these documents in our bundle
 	selector = selector.
 		ByKind(SecretKind).
-		ByNamespace(netConfDocNamespace).
-		ByName(netConfDocName)
+		ByNamespace(namespace).
+		ByName(name)
 
 	return selector, nil
 }
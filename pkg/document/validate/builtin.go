@@ -0,0 +1,159 @@
+package validate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// validateType checks that generic JSON value can be decoded into the Go type
+// of the built-in kubernetes object without unknown fields and type mismatches.
+// Go types carry no schema constraints, so required fields, enums and formats
+// are not checked
+func validateType(path *field.Path, value interface{}, t reflect.Type) field.ErrorList {
+	if value == nil {
+		return nil
+	}
+
+	// types with custom decoding, e.g. resource.Quantity or metav1.Time
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return field.ErrorList{field.Invalid(path, value, err.Error())}
+		}
+		if err = json.Unmarshal(data, reflect.New(t).Interface()); err != nil {
+			return field.ErrorList{field.Invalid(path, value, err.Error())}
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return validateType(path, value, t.Elem())
+	case reflect.Interface:
+		return nil
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return field.ErrorList{typeError(path, value, "object")}
+		}
+		fields := jsonFields(t)
+		var errs field.ErrorList
+		for _, key := range sortedKeys(obj) {
+			fieldType, found := fields[key]
+			if !found {
+				errs = append(errs, field.Forbidden(path.Child(key), "unknown field"))
+				continue
+			}
+			errs = append(errs, validateType(path.Child(key), obj[key], fieldType)...)
+		}
+		return errs
+	case reflect.Map:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return field.ErrorList{typeError(path, value, "object")}
+		}
+		var errs field.ErrorList
+		for _, key := range sortedKeys(obj) {
+			errs = append(errs, validateType(path.Key(key), obj[key], t.Elem())...)
+		}
+		return errs
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return validateBytes(path, value)
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			return field.ErrorList{typeError(path, value, "array")}
+		}
+		var errs field.ErrorList
+		for i, item := range list {
+			errs = append(errs, validateType(path.Index(i), item, t.Elem())...)
+		}
+		return errs
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			return field.ErrorList{typeError(path, value, "string")}
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return field.ErrorList{typeError(path, value, "boolean")}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if number, ok := value.(float64); !ok || number != math.Trunc(number) {
+			return field.ErrorList{typeError(path, value, "integer")}
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := value.(float64); !ok {
+			return field.ErrorList{typeError(path, value, "number")}
+		}
+	}
+	return nil
+}
+
+func validateBytes(path *field.Path, value interface{}) field.ErrorList {
+	encoded, ok := value.(string)
+	if !ok {
+		return field.ErrorList{typeError(path, value, "base64 encoded string")}
+	}
+	if _, err := base64.StdEncoding.DecodeString(encoded); err != nil {
+		return field.ErrorList{field.Invalid(path, value, err.Error())}
+	}
+	return nil
+}
+
+func typeError(path *field.Path, value interface{}, expected string) *field.Error {
+	return field.Invalid(path, value, fmt.Sprintf("must be of type %s", expected))
+}
+
+// jsonFields returns types of struct fields by their JSON names, fields of
+// embedded structs are inlined the same way encoding/json does
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for embeddedName, embeddedType := range jsonFields(embedded) {
+					fields[embeddedName] = embeddedType
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,106 @@
+package validate
+
+import (
+	"encoding/json"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+// schemaValidator returns errors of the object not matching the schema
+type schemaValidator func(obj interface{}) field.ErrorList
+
+// crdSchemas returns OpenAPI v3 schema validators of custom resources
+// defined by CustomResourceDefinitions found in the bundle
+func crdSchemas(bundle document.Bundle) (map[schema.GroupVersionKind]schemaValidator, error) {
+	schemas := make(map[schema.GroupVersionKind]schemaValidator)
+
+	v1CRDs, err := bundle.GetByGvk(apiextensionsv1.GroupName, "v1", "CustomResourceDefinition")
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range v1CRDs {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err = unmarshalDocument(doc, crd); err != nil {
+			return nil, err
+		}
+		for _, version := range crd.Spec.Versions {
+			if version.Schema == nil {
+				continue
+			}
+			internal := &apiextensions.CustomResourceValidation{}
+			err = apiextensionsv1.Convert_v1_CustomResourceValidation_To_apiextensions_CustomResourceValidation(
+				version.Schema, internal, nil)
+			if err != nil {
+				return nil, err
+			}
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+			if err = addSchema(schemas, gvk, internal); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// for legacy support
+	v1beta1CRDs, err := bundle.GetByGvk(apiextensionsv1beta1.GroupName, "v1beta1", "CustomResourceDefinition")
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range v1beta1CRDs {
+		crd := &apiextensionsv1beta1.CustomResourceDefinition{}
+		if err = unmarshalDocument(doc, crd); err != nil {
+			return nil, err
+		}
+		versions := crd.Spec.Versions
+		if len(versions) == 0 {
+			versions = []apiextensionsv1beta1.CustomResourceDefinitionVersion{{Name: crd.Spec.Version}}
+		}
+		for _, version := range versions {
+			// schema of the version takes precedence over the common one
+			crdSchema := version.Schema
+			if crdSchema == nil {
+				crdSchema = crd.Spec.Validation
+			}
+			if crdSchema == nil {
+				continue
+			}
+			internal := &apiextensions.CustomResourceValidation{}
+			err = apiextensionsv1beta1.Convert_v1beta1_CustomResourceValidation_To_apiextensions_CustomResourceValidation(
+				crdSchema, internal, nil)
+			if err != nil {
+				return nil, err
+			}
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+			if err = addSchema(schemas, gvk, internal); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return schemas, nil
+}
+
+func addSchema(schemas map[schema.GroupVersionKind]schemaValidator,
+	gvk schema.GroupVersionKind, crdValidation *apiextensions.CustomResourceValidation) error {
+	validator, _, err := validation.NewSchemaValidator(crdValidation)
+	if err != nil {
+		return err
+	}
+	schemas[gvk] = func(obj interface{}) field.ErrorList {
+		return validation.ValidateCustomResource(nil, obj, validator)
+	}
+	return nil
+}
+
+func unmarshalDocument(doc document.Document, obj interface{}) error {
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, obj)
+}
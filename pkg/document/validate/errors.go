@@ -0,0 +1,28 @@
+package validate
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"opendev.org/airship/airshipctl/pkg/document/diff"
+)
+
+// Error describes a single field of the document not matching its schema
+type Error struct {
+	ID    diff.ID
+	Field *field.Error
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.ID, e.Field.Error())
+}
+
+// ErrValidationFailed returned if any of the documents is invalid
+type ErrValidationFailed struct {
+	Errors int
+}
+
+func (e ErrValidationFailed) Error() string {
+	return fmt.Sprintf("Validation failed with %d error(s)", e.Errors)
+}
@@ -0,0 +1,14 @@
+package validate
+
+import (
+	"opendev.org/airship/airshipctl/pkg/environment"
+)
+
+// Settings for document validation
+type Settings struct {
+	*environment.AirshipCTLSettings
+	// ClusterType is the cluster type whose entrypoint is validated
+	ClusterType string
+	// Phase is the phase within the cluster type to be validated
+	Phase string
+}
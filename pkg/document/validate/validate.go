@@ -0,0 +1,159 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/diff"
+	"opendev.org/airship/airshipctl/pkg/document/policy"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// Validator checks documents against OpenAPI v3 schemas of custom resources
+// defined in the bundle, built-in kubernetes objects are only checked for
+// unknown fields and type mismatches against their Go types
+type Validator struct {
+	crdSchemas map[schema.GroupVersionKind]schemaValidator
+	scheme     *runtime.Scheme
+}
+
+// NewValidator returns Validator aware of CustomResourceDefinitions found in the bundle
+func NewValidator(bundle document.Bundle) (*Validator, error) {
+	schemas, err := crdSchemas(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	builtin := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		scheme.AddToScheme,
+		apiextensionsv1.AddToScheme,
+		apiextensionsv1beta1.AddToScheme,
+	} {
+		if err = addToScheme(builtin); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Validator{crdSchemas: schemas, scheme: builtin}, nil
+}
+
+// Validate returns validation errors of every document of the bundle
+func (v *Validator) Validate(bundle document.Bundle) ([]Error, error) {
+	docs, err := bundle.GetAllDocuments()
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []Error
+	for _, doc := range docs {
+		docErrs, err := v.ValidateDocument(doc)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, docErrs...)
+	}
+	return errs, nil
+}
+
+// ValidateDocument returns validation errors of the document, documents
+// of unknown kinds are not validated
+func (v *Validator) ValidateDocument(doc document.Document) ([]Error, error) {
+	id, err := diff.NewID(doc)
+	if err != nil {
+		return nil, err
+	}
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var obj interface{}
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	gvk := schema.FromAPIVersionAndKind(id.APIVersion, id.Kind)
+	var fieldErrs field.ErrorList
+	if validateSchema, found := v.crdSchemas[gvk]; found {
+		fieldErrs = validateSchema(obj)
+	} else if typed, err := v.scheme.New(gvk); err == nil {
+		fieldErrs = validateType(nil, obj, reflect.TypeOf(typed))
+	} else {
+		log.Debugf("No schema found for document %s, skipping validation", id)
+		return nil, nil
+	}
+
+	errs := make([]Error, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		errs = append(errs, Error{ID: id, Field: fieldErr})
+	}
+	return errs, nil
+}
+
+// Validate builds the document bundle for the cluster type and phase of the
+// current context and writes every validation error, policy violation and
+// BareMetalHost reference problem to out
+func (s *Settings) Validate(out io.Writer) error {
+	conf := s.Config()
+	if err := conf.EnsureComplete(); err != nil {
+		return err
+	}
+
+	entryPoint, err := conf.CurrentContextEntryPoint(s.ClusterType, s.Phase)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := document.NewBundleByPath(entryPoint)
+	if err != nil {
+		return err
+	}
+
+	validator, err := NewValidator(bundle)
+	if err != nil {
+		return err
+	}
+
+	errs, err := validator.Validate(bundle)
+	if err != nil {
+		return err
+	}
+	violations, err := policy.Check(bundle)
+	if err != nil {
+		return err
+	}
+	problems, err := document.CheckBMHReferences(bundle)
+	if err != nil {
+		return err
+	}
+
+	for _, validationErr := range errs {
+		if _, err = fmt.Fprintln(out, validationErr.Error()); err != nil {
+			return err
+		}
+	}
+	for _, violation := range violations {
+		if _, err = fmt.Fprintln(out, violation.Error()); err != nil {
+			return err
+		}
+	}
+	for _, problem := range problems {
+		if _, err = fmt.Fprintln(out, problem.String()); err != nil {
+			return err
+		}
+	}
+	if failed := len(errs) + len(violations) + len(problems); failed > 0 {
+		return ErrValidationFailed{Errors: failed}
+	}
+	return nil
+}
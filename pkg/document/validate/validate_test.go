@@ -0,0 +1,71 @@
+package validate_test
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document/validate"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const (
+	airshipConfigFile = "testdata/config.yaml"
+	kubeconfigPath    = "testdata/kubeconfig.yaml"
+	bundlePath        = "testdata/primary/site/test-site/ephemeral/initinfra"
+)
+
+var expectedErrors = []string{
+	"metal3.io/v1alpha1 BareMetalHost test/node-2 spec.online",
+	"metal3.io/v1alpha1 BareMetalHost test/node-2 spec.bmc.credentialsName",
+	"apps/v1 Deployment test/test-deployment spec.replicas",
+	"apps/v1 Deployment test/test-deployment spec.unknownField",
+}
+
+func TestValidate(t *testing.T) {
+	bundle := testutil.NewTestBundle(t, bundlePath)
+
+	validator, err := validate.NewValidator(bundle)
+	require.NoError(t, err)
+
+	errs, err := validator.Validate(bundle)
+	require.NoError(t, err)
+
+	actual := make([]string, 0, len(errs))
+	for _, validationErr := range errs {
+		actual = append(actual, fmt.Sprintf("%s %s", validationErr.ID, validationErr.Field.Field))
+	}
+	assert.ElementsMatch(t, expectedErrors, actual)
+}
+
+func TestSettingsValidate(t *testing.T) {
+	rs := &environment.AirshipCTLSettings{}
+	acp, err := filepath.Abs(airshipConfigFile)
+	require.NoError(t, err)
+	kcp, err := filepath.Abs(kubeconfigPath)
+	require.NoError(t, err)
+	rs.SetAirshipConfigPath(acp)
+	rs.SetKubeConfigPath(kcp)
+	rs.InitConfig()
+
+	settings := &validate.Settings{
+		AirshipCTLSettings: rs,
+		ClusterType:        "ephemeral",
+		Phase:              "initinfra",
+	}
+
+	out := &bytes.Buffer{}
+	err = settings.Validate(out)
+	// schema errors, a policy violation and two BareMetalHost reference problems
+	assert.Equal(t, validate.ErrValidationFailed{Errors: len(expectedErrors) + 3}, err)
+	assert.Contains(t, out.String(), "apps/v1 Deployment test/test-deployment: spec.unknownField: Forbidden: unknown field")
+	assert.Contains(t, out.String(), "metal3.io/v1alpha1 BareMetalHost test/node-2: spec.bmc.credentialsName: Required value")
+	assert.Contains(t, out.String(), `v1 ConfigMap test/test-config: missing label "app" (rule configmap-app-label)`)
+	assert.Contains(t, out.String(),
+		`BareMetalHost test/node-1: spec.bmc.credentialsName: Secret "node-1-bmc-secret" not found`)
+}
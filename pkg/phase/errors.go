@@ -0,0 +1,54 @@
+package phase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrPhaseNotFound returned if there is no phase document with the name
+type ErrPhaseNotFound struct {
+	Name string
+}
+
+func (e ErrPhaseNotFound) Error() string {
+	return fmt.Sprintf("Phase %q is not found", e.Name)
+}
+
+// ErrDuplicatePhase returned if several phase documents have the same name
+type ErrDuplicatePhase struct {
+	Name string
+}
+
+func (e ErrDuplicatePhase) Error() string {
+	return fmt.Sprintf("Phase %q is declared more than once", e.Name)
+}
+
+// ErrUnknownPrerequisite returned if the phase depends on undeclared phase
+type ErrUnknownPrerequisite struct {
+	Phase        string
+	Prerequisite string
+}
+
+func (e ErrUnknownPrerequisite) Error() string {
+	return fmt.Sprintf("Phase %q depends on unknown phase %q", e.Phase, e.Prerequisite)
+}
+
+// ErrUnknownExecutor returned if there is no executor registered with the name
+type ErrUnknownExecutor struct {
+	Executor  string
+	Supported []string
+}
+
+func (e ErrUnknownExecutor) Error() string {
+	return fmt.Sprintf("Unknown phase executor %q, supported executors: %s",
+		e.Executor, strings.Join(e.Supported, ", "))
+}
+
+// ErrCyclicDependency returned if phases depend on each other
+type ErrCyclicDependency struct {
+	Phases []string
+}
+
+func (e ErrCyclicDependency) Error() string {
+	return fmt.Sprintf("Phases %s have cyclic dependencies", strings.Join(e.Phases, ", "))
+}
@@ -0,0 +1,88 @@
+package phase
+
+import (
+	"sort"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+// KubernetesApplyExecutor is the name of the default executor, which applies
+// phase documents to the cluster
+const KubernetesApplyExecutor = "kubernetes-apply"
+
+// Executor deploys the phase bundle
+type Executor interface {
+	Execute(phase *Phase, bundle document.Bundle) error
+}
+
+// ExecutorFactory returns the executor configured with the settings
+type ExecutorFactory func(settings *Settings) (Executor, error)
+
+var executors = map[string]ExecutorFactory{
+	KubernetesApplyExecutor: newKubernetesApply,
+}
+
+// RegisterExecutor makes the executor available for phases by its name,
+// executor registered earlier with the same name is replaced
+func RegisterExecutor(name string, factory ExecutorFactory) {
+	executors[name] = factory
+}
+
+// Executors returns sorted names of registered executors
+func Executors() []string {
+	names := make([]string, 0, len(executors))
+	for name := range executors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetExecutor returns the executor registered with the name
+func GetExecutor(name string, settings *Settings) (Executor, error) {
+	factory, found := executors[name]
+	if !found {
+		return nil, ErrUnknownExecutor{Executor: name, Supported: Executors()}
+	}
+	return factory(settings)
+}
+
+// kubernetesApply applies documents of the phase to the cluster the same way
+// cluster initinfra does
+type kubernetesApply struct {
+	client client.Interface
+	dryRun bool
+	prune  bool
+}
+
+func newKubernetesApply(settings *Settings) (Executor, error) {
+	kclient, err := settings.KubeClient()
+	if err != nil {
+		return nil, err
+	}
+	return &kubernetesApply{client: kclient, dryRun: settings.DryRun, prune: settings.Prune}, nil
+}
+
+func (e *kubernetesApply) Execute(phase *Phase, bundle document.Bundle) error {
+	kctl := e.client.Kubectl()
+	ao, err := kctl.ApplyOptions()
+	if err != nil {
+		return err
+	}
+
+	ao.SetDryRun(e.dryRun)
+	// If prune is true, set selector for pruning documents of this phase
+	if e.prune {
+		ao.SetPrune(document.BaseAirshipSelector + "/phase = " + phase.Name)
+	}
+
+	docs, err := bundle.Select(document.NewDeployToK8sSelector())
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return document.ErrDocNotFound{}
+	}
+	return kctl.Apply(docs, ao)
+}
@@ -0,0 +1,191 @@
+package phase
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/document/policy"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// Phases returns phases declared in the phases bundle of the current
+// context manifest sorted by name
+func (s *Settings) Phases() ([]*Phase, error) {
+	conf := s.Config()
+	if err := conf.EnsureComplete(); err != nil {
+		return nil, err
+	}
+
+	phasesPath, err := conf.CurrentContextBundlePath(PhasesPath)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := document.NewBundleByPath(phasesPath)
+	if err != nil {
+		return nil, err
+	}
+	return LoadPhases(bundle)
+}
+
+// LoadPhases returns phases declared in the bundle sorted by name
+func LoadPhases(bundle document.Bundle) ([]*Phase, error) {
+	docs, err := bundle.GetByGvk(Group, Version, Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	phases := make([]*Phase, 0, len(docs))
+	names := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		data, err := doc.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		phase := &Phase{}
+		if err = json.Unmarshal(data, phase); err != nil {
+			return nil, err
+		}
+		phase.Name = doc.GetName()
+		if names[phase.Name] {
+			return nil, ErrDuplicatePhase{Name: phase.Name}
+		}
+		if err = config.ValidClusterType(phase.Config.ClusterType); err != nil {
+			return nil, err
+		}
+		if phase.Config.Executor == "" {
+			phase.Config.Executor = KubernetesApplyExecutor
+		}
+		names[phase.Name] = true
+		phases = append(phases, phase)
+	}
+
+	for _, phase := range phases {
+		for _, prerequisite := range phase.Config.Prerequisites {
+			if !names[prerequisite] {
+				return nil, ErrUnknownPrerequisite{Phase: phase.Name, Prerequisite: prerequisite}
+			}
+		}
+	}
+
+	sort.Slice(phases, func(i, j int) bool { return phases[i].Name < phases[j].Name })
+	return phases, nil
+}
+
+// GetPhase returns the phase by its name
+func (s *Settings) GetPhase(name string) (*Phase, error) {
+	phases, err := s.Phases()
+	if err != nil {
+		return nil, err
+	}
+	for _, phase := range phases {
+		if phase.Name == name {
+			return phase, nil
+		}
+	}
+	return nil, ErrPhaseNotFound{Name: name}
+}
+
+// KubeClient returns the client of the settings initializing it from
+// kubeconfig if it is not set
+func (s *Settings) KubeClient() (client.Interface, error) {
+	if s.Client == nil {
+		var err error
+		if s.Client, err = client.NewClient(s.AirshipCTLSettings); err != nil {
+			return nil, err
+		}
+	}
+	return s.Client, nil
+}
+
+// EntryPoint returns path of the phase bundle
+func (s *Settings) EntryPoint(phase *Phase) (string, error) {
+	entryPoint := phase.Config.DocumentEntryPoint
+	if entryPoint == "" {
+		entryPoint = path.Join(phase.Config.ClusterType, phase.Name)
+	}
+	return s.Config().CurrentContextBundlePath(entryPoint)
+}
+
+// Bundle builds the document bundle of the phase
+func (s *Settings) Bundle(phase *Phase) (document.Bundle, error) {
+	entryPoint, err := s.EntryPoint(phase)
+	if err != nil {
+		return nil, err
+	}
+	return document.NewBundleByPath(entryPoint)
+}
+
+// List writes a table of phases to out
+func (s *Settings) List(out io.Writer) error {
+	phases, err := s.Phases()
+	if err != nil {
+		return err
+	}
+
+	w := util.NewTabWriter(out)
+	fmt.Fprintln(w, "NAME\tCLUSTER TYPE\tEXECUTOR\tPREREQUISITES")
+	for _, phase := range phases {
+		prerequisites := strings.Join(phase.Config.Prerequisites, ",")
+		if prerequisites == "" {
+			prerequisites = "<none>"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", phase.Name, phase.Config.ClusterType, phase.Config.Executor, prerequisites)
+	}
+	return w.Flush()
+}
+
+// Render writes documents of the phase bundle to out in the output format
+func (s *Settings) Render(name string, out io.Writer) error {
+	format := s.Output
+	if format == "" {
+		format = document.YAMLFormat
+	}
+	encoder, err := document.GetEncoder(format)
+	if err != nil {
+		return err
+	}
+
+	phase, err := s.GetPhase(name)
+	if err != nil {
+		return err
+	}
+	bundle, err := s.Bundle(phase)
+	if err != nil {
+		return err
+	}
+	return bundle.Encode(out, encoder)
+}
+
+// Apply deploys documents of the phase with its executor
+func (s *Settings) Apply(name string) error {
+	phase, err := s.GetPhase(name)
+	if err != nil {
+		return err
+	}
+	bundle, err := s.Bundle(phase)
+	if err != nil {
+		return err
+	}
+	return s.Execute(phase, bundle)
+}
+
+// Execute deploys the phase bundle with the executor of the phase, bundles
+// violating their policy rules are never deployed
+func (s *Settings) Execute(phase *Phase, bundle document.Bundle) error {
+	if err := policy.Enforce(bundle); err != nil {
+		return err
+	}
+
+	executor, err := GetExecutor(phase.Config.Executor, s)
+	if err != nil {
+		return err
+	}
+	return executor.Execute(phase, bundle)
+}
@@ -0,0 +1,196 @@
+package phase_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/pkg/k8s/kubectl"
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/testutil"
+	"opendev.org/airship/airshipctl/testutil/k8sutils"
+)
+
+const (
+	kubeconfigPath    = "testdata/kubeconfig.yaml"
+	filenameRC        = "testdata/primary/site/test-site/ephemeral/initinfra/replicationcontroller.yaml"
+	airshipConfigFile = "testdata/config.yaml"
+)
+
+func TestPhases(t *testing.T) {
+	settings := makeSettings(t)
+
+	phases, err := settings.Phases()
+	require.NoError(t, err)
+	require.Len(t, phases, 3)
+
+	assert.Equal(t, "initinfra", phases[0].Name)
+	assert.Equal(t, phase.KubernetesApplyExecutor, phases[0].Config.Executor)
+	assert.Equal(t, "workers", phases[2].Name)
+	assert.Equal(t, []string{"initinfra"}, phases[2].Config.Prerequisites)
+
+	entryPoint, err := settings.EntryPoint(phases[0])
+	require.NoError(t, err)
+	assert.Equal(t, "testdata/primary/site/test-site/ephemeral/initinfra", entryPoint)
+
+	_, err = settings.GetPhase("controlplane")
+	assert.Equal(t, phase.ErrPhaseNotFound{Name: "controlplane"}, err)
+}
+
+func TestLoadPhases(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		expectedErr error
+	}{
+		{
+			fixture:     "testdata/duplicate",
+			expectedErr: phase.ErrDuplicatePhase{Name: "initinfra"},
+		},
+		{
+			fixture:     "testdata/prerequisite",
+			expectedErr: phase.ErrUnknownPrerequisite{Phase: "workers", Prerequisite: "controlplane"},
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := phase.LoadPhases(testutil.NewTestBundle(t, tt.fixture))
+		assert.Equal(t, tt.expectedErr, err)
+	}
+}
+
+func TestList(t *testing.T) {
+	out := &bytes.Buffer{}
+	require.NoError(t, makeSettings(t).List(out))
+
+	expected := "NAME               CLUSTER TYPE   EXECUTOR           PREREQUISITES\n" +
+		"initinfra          ephemeral      kubernetes-apply   <none>\n" +
+		"unknown-executor   target         unknown            <none>\n" +
+		"workers            target         kubernetes-apply   initinfra\n"
+	assert.Equal(t, expected, out.String())
+}
+
+func TestRender(t *testing.T) {
+	out := &bytes.Buffer{}
+	require.NoError(t, makeSettings(t).Render("workers", out))
+	assert.Contains(t, out.String(), "kind: ConfigMap")
+	assert.NotContains(t, out.String(), "kind: ReplicationController")
+
+	settings := makeSettings(t)
+	settings.Output = "json"
+	out.Reset()
+	require.NoError(t, settings.Render("workers", out))
+	assert.Contains(t, out.String(), `"kind": "ConfigMap"`)
+
+	settings.Output = "xml"
+	assert.IsType(t, document.ErrUnknownOutputFormat{}, settings.Render("workers", out))
+}
+
+func TestApply(t *testing.T) {
+	tf := k8sutils.NewFakeFactoryForRC(t, filenameRC)
+	defer tf.Cleanup()
+
+	settings := makeSettings(t)
+	settings.DryRun = true
+	settings.Client = fake.Client{
+		MockKubectl: func() kubectl.Interface { return kubectl.NewKubectl(tf) },
+	}
+
+	tests := []struct {
+		name        string
+		prune       bool
+		expectedErr error
+	}{
+		{
+			name: "initinfra",
+		},
+		{
+			name:  "initinfra",
+			prune: true,
+		},
+		{
+			name:        "controlplane",
+			expectedErr: phase.ErrPhaseNotFound{Name: "controlplane"},
+		},
+		{
+			name: "unknown-executor",
+			expectedErr: phase.ErrUnknownExecutor{
+				Executor:  "unknown",
+				Supported: []string{phase.KubernetesApplyExecutor},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		settings.Prune = tt.prune
+		assert.Equal(t, tt.expectedErr, settings.Apply(tt.name))
+	}
+}
+
+func makeSettings(t *testing.T) *phase.Settings {
+	t.Helper()
+	rs := &environment.AirshipCTLSettings{}
+
+	akp, err := filepath.Abs(kubeconfigPath)
+	require.NoError(t, err)
+
+	adir, err := filepath.Abs(airshipConfigFile)
+	require.NoError(t, err)
+
+	rs.SetAirshipConfigPath(adir)
+	rs.SetKubeConfigPath(akp)
+	rs.InitConfig()
+
+	return &phase.Settings{AirshipCTLSettings: rs}
+}
+
+func TestSort(t *testing.T) {
+	makePhase := func(name string, prerequisites ...string) *phase.Phase {
+		return &phase.Phase{Name: name, Config: phase.Config{Prerequisites: prerequisites}}
+	}
+
+	tests := []struct {
+		name        string
+		phases      []*phase.Phase
+		expected    []string
+		expectedErr error
+	}{
+		{
+			name: "dependencies",
+			phases: []*phase.Phase{
+				makePhase("controlplane", "initinfra"),
+				makePhase("initinfra"),
+				makePhase("workers", "controlplane", "initinfra"),
+			},
+			expected: []string{"initinfra", "controlplane", "workers"},
+		},
+		{
+			name: "cycle",
+			phases: []*phase.Phase{
+				makePhase("a", "b"),
+				makePhase("b", "a"),
+				makePhase("c"),
+			},
+			expectedErr: phase.ErrCyclicDependency{Phases: []string{"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, err := phase.Sort(tt.phases)
+			assert.Equal(t, tt.expectedErr, err)
+
+			var names []string
+			for _, p := range sorted {
+				names = append(names, p.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package phase
+
+// Sort orders phases so that every phase follows its prerequisites, the order
+// is stable for the same list of phases
+func Sort(phases []*Phase) ([]*Phase, error) {
+	sorted := make([]*Phase, 0, len(phases))
+	done := make(map[string]bool, len(phases))
+	pending := phases
+
+	for len(pending) > 0 {
+		var blocked []*Phase
+		for _, phase := range pending {
+			if prerequisitesDone(phase, done) {
+				sorted = append(sorted, phase)
+				done[phase.Name] = true
+				continue
+			}
+			blocked = append(blocked, phase)
+		}
+
+		if len(blocked) == len(pending) {
+			names := make([]string, 0, len(blocked))
+			for _, phase := range blocked {
+				names = append(names, phase.Name)
+			}
+			return nil, ErrCyclicDependency{Phases: names}
+		}
+		pending = blocked
+	}
+	return sorted, nil
+}
+
+func prerequisitesDone(phase *Phase, done map[string]bool) bool {
+	for _, prerequisite := range phase.Config.Prerequisites {
+		if !done[prerequisite] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package phase
+
+import (
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+)
+
+// Group, version and kind of documents declaring phases
+const (
+	Group   = "airshipit.org"
+	Version = "v1alpha1"
+	Kind    = "Phase"
+)
+
+// PhasesPath is a path of the bundle with phase documents relative to the
+// sub path of the current context manifest
+const PhasesPath = "phases"
+
+// Phase is a document declaring a step of the site deployment
+type Phase struct {
+	// Name is the name of the phase document
+	Name string `json:"-"`
+	// Config describes how the phase is deployed
+	Config Config `json:"config"`
+}
+
+// Config holds phase settings
+type Config struct {
+	// ClusterType is the type of the cluster the phase is deployed to
+	ClusterType string `json:"clusterType"`
+	// DocumentEntryPoint is a path of the phase bundle relative to the sub
+	// path of the current context manifest, <clusterType>/<name> by default
+	DocumentEntryPoint string `json:"documentEntryPoint,omitempty"`
+	// Prerequisites lists phases that must be deployed before this one
+	Prerequisites []string `json:"prerequisites,omitempty"`
+	// Executor is the name of the executor deploying the phase,
+	// kubernetes-apply by default
+	Executor string `json:"executor,omitempty"`
+}
+
+// Settings for phase commands
+type Settings struct {
+	*environment.AirshipCTLSettings
+	// Client is used by executors to reach the cluster, it is initialized
+	// from kubeconfig if not set
+	Client client.Interface
+
+	DryRun bool
+	Prune  bool
+	// Output is the format documents are rendered in, YAML if it is empty
+	Output string
+}
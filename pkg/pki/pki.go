@@ -0,0 +1,240 @@
+package pki
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+// Suffixes of names of Secret documents holding cluster credentials, names
+// match the ones used by Cluster API so that providers pick up the secrets
+const (
+	CASuffix             = "ca"
+	EtcdCASuffix         = "etcd"
+	FrontProxyCASuffix   = "proxy"
+	ServiceAccountSuffix = "sa"
+	AdminSuffix          = "admin"
+)
+
+// Subject of the admin client certificate
+const (
+	AdminCommonName   = "kubernetes-admin"
+	AdminOrganization = "system:masters"
+)
+
+const publicKeyPEMType = "PUBLIC KEY"
+
+// ClusterPKI holds certificate authorities and credentials of a cluster
+type ClusterPKI struct {
+	ClusterName       string
+	CA                *secret.KeyPair
+	EtcdCA            *secret.KeyPair
+	FrontProxyCA      *secret.KeyPair
+	ServiceAccountKey *rsa.PrivateKey
+	Admin             *secret.KeyPair
+}
+
+// Settings for secret generate pki command
+type Settings struct {
+	*environment.AirshipCTLSettings
+	ClusterName string
+	ClusterType string
+	// Server is the address of the API server of the cluster
+	Server    string
+	Namespace string
+	// Dst is the file to write Secret documents to, credentials found in
+	// the file are kept
+	Dst     string
+	Encrypt bool
+}
+
+// Generate writes credentials of the cluster to Dst and adds the cluster and
+// its admin user to the airship config
+func (s *Settings) Generate(out io.Writer) error {
+	if err := config.ValidClusterType(s.ClusterType); err != nil {
+		return err
+	}
+
+	existing, err := secret.ReadSecrets(s.Dst)
+	if err != nil {
+		return err
+	}
+	p, err := NewClusterPKI(s.ClusterName, existing)
+	if err != nil {
+		return err
+	}
+	secrets, err := p.Secrets(s.Namespace)
+	if err != nil {
+		return err
+	}
+	if err = secret.WriteSecrets(out, s.Dst, secrets, s.Encrypt); err != nil {
+		return err
+	}
+
+	conf := s.Config()
+	if err = p.AddToConfig(conf, s.ClusterType, s.Server); err != nil {
+		return err
+	}
+	if err = conf.PersistConfig(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Cluster %q of type %q and user %q added.\n", s.ClusterName, s.ClusterType, p.AdminName())
+	return nil
+}
+
+// NewClusterPKI returns credentials of the cluster found in the Secret
+// documents, missing credentials are generated. The admin certificate is
+// regenerated if the cluster certificate authority has changed
+func NewClusterPKI(clusterName string, secrets []map[string]interface{}) (*ClusterPKI, error) {
+	p := &ClusterPKI{ClusterName: clusterName}
+	byName := make(map[string]map[string]interface{}, len(secrets))
+	for _, obj := range secrets {
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		byName[name] = obj
+	}
+
+	var err error
+	// common names of the authorities are the ones used by kubeadm
+	authorities := []struct {
+		suffix     string
+		commonName string
+		ca         **secret.KeyPair
+	}{
+		{CASuffix, "kubernetes", &p.CA},
+		{EtcdCASuffix, "etcd-ca", &p.EtcdCA},
+		{FrontProxyCASuffix, "front-proxy-ca", &p.FrontProxyCA},
+	}
+	for _, authority := range authorities {
+		if *authority.ca, err = parseKeyPair(byName[p.secretName(authority.suffix)]); err != nil {
+			return nil, err
+		}
+		if *authority.ca == nil {
+			*authority.ca, err = secret.NewCertificateAuthority(secret.CertificateConfig{CommonName: authority.commonName})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if obj := byName[p.secretName(ServiceAccountSuffix)]; obj != nil {
+		if p.ServiceAccountKey, err = secret.ParsePrivateKey(secret.SecretData(obj, secret.TLSKeyKey)); err != nil {
+			return nil, err
+		}
+	} else if p.ServiceAccountKey, err = rsa.GenerateKey(rand.Reader, secret.DefaultKeyBits); err != nil {
+		return nil, err
+	}
+
+	obj := byName[p.AdminName()]
+	if obj != nil && bytes.Equal(secret.SecretData(obj, secret.CACertKey), p.CA.CertificatePEM()) {
+		if p.Admin, err = parseKeyPair(obj); err != nil {
+			return nil, err
+		}
+	}
+	if p.Admin == nil {
+		p.Admin, err = p.CA.NewCertificate(secret.CertificateConfig{
+			CommonName:   AdminCommonName,
+			Organization: []string{AdminOrganization},
+			Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// AdminName returns the name of the admin user of the cluster
+func (p *ClusterPKI) AdminName() string {
+	return p.secretName(AdminSuffix)
+}
+
+// Secrets returns Secret documents holding the credentials
+func (p *ClusterPKI) Secrets(namespace string) ([]map[string]interface{}, error) {
+	publicKey, err := x509.MarshalPKIXPublicKey(&p.ServiceAccountKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []map[string]interface{}{
+		keyPairSecret(p.secretName(CASuffix), namespace, p.CA, nil),
+		keyPairSecret(p.secretName(EtcdCASuffix), namespace, p.EtcdCA, nil),
+		keyPairSecret(p.secretName(FrontProxyCASuffix), namespace, p.FrontProxyCA, nil),
+		secret.NewSecret(p.secretName(ServiceAccountSuffix), namespace, "Opaque", map[string][]byte{
+			secret.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: publicKeyPEMType, Bytes: publicKey}),
+			secret.TLSKeyKey:  secret.EncodePrivateKey(p.ServiceAccountKey),
+		}),
+		keyPairSecret(p.AdminName(), namespace, p.Admin, p.CA),
+	}, nil
+}
+
+// AddToConfig adds the cluster of the type and the admin user of the cluster
+// to the config, the entries are updated if they exist
+func (p *ClusterPKI) AddToConfig(conf *config.Config, clusterType, server string) error {
+	clusterOptions := &config.ClusterOptions{
+		Name:        p.ClusterName,
+		ClusterType: clusterType,
+		Server:      server,
+	}
+	cluster, err := conf.GetCluster(p.ClusterName, clusterType)
+	if err != nil {
+		cluster, err = conf.AddCluster(clusterOptions)
+	} else {
+		cluster, err = conf.ModifyCluster(cluster, clusterOptions)
+	}
+	if err != nil {
+		return err
+	}
+	kubeCluster := cluster.KubeCluster()
+	if kubeCluster == nil {
+		return config.ErrMissingConfig{What: fmt.Sprintf("Kubeconfig cluster %q", cluster.NameInKubeconf)}
+	}
+	kubeCluster.CertificateAuthorityData = p.CA.CertificatePEM()
+	kubeCluster.CertificateAuthority = ""
+	kubeCluster.InsecureSkipTLSVerify = false
+
+	authInfo, err := conf.GetAuthInfo(p.AdminName())
+	if err != nil {
+		authInfo = conf.AddAuthInfo(&config.AuthInfoOptions{Name: p.AdminName()})
+	}
+	kubeAuthInfo := authInfo.KubeAuthInfo()
+	if kubeAuthInfo == nil {
+		return config.ErrMissingConfig{What: fmt.Sprintf("Kubeconfig user credentials %q", p.AdminName())}
+	}
+	kubeAuthInfo.ClientCertificateData = p.Admin.CertificatePEM()
+	kubeAuthInfo.ClientKeyData = p.Admin.KeyPEM()
+	kubeAuthInfo.ClientCertificate = ""
+	kubeAuthInfo.ClientKey = ""
+	return nil
+}
+
+func (p *ClusterPKI) secretName(suffix string) string {
+	return p.ClusterName + "-" + suffix
+}
+
+func keyPairSecret(name, namespace string, kp, ca *secret.KeyPair) map[string]interface{} {
+	data := map[string][]byte{
+		secret.TLSCertKey: kp.CertificatePEM(),
+		secret.TLSKeyKey:  kp.KeyPEM(),
+	}
+	if ca != nil {
+		data[secret.CACertKey] = ca.CertificatePEM()
+	}
+	return secret.NewSecret(name, namespace, "kubernetes.io/tls", data)
+}
+
+// parseKeyPair returns KeyPair of the Secret object or nil if the object is nil
+func parseKeyPair(obj map[string]interface{}) (*secret.KeyPair, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	return secret.ParseKeyPair(secret.SecretData(obj, secret.TLSCertKey), secret.SecretData(obj, secret.TLSKeyKey))
+}
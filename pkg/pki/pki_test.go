@@ -0,0 +1,85 @@
+package pki_test
+
+import (
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/pki"
+	"opendev.org/airship/airshipctl/pkg/secret"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+func TestNewClusterPKI(t *testing.T) {
+	p, err := pki.NewClusterPKI("target-cluster", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "kubernetes", p.CA.Certificate.Subject.CommonName)
+	assert.True(t, p.EtcdCA.Certificate.IsCA)
+	assert.Equal(t, []string{pki.AdminOrganization}, p.Admin.Certificate.Subject.Organization)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(p.CA.Certificate)
+	_, err = p.Admin.Certificate.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	assert.NoError(t, err)
+
+	secrets, err := p.Secrets("default")
+	require.NoError(t, err)
+	names := make([]string, 0, len(secrets))
+	for _, obj := range secrets {
+		names = append(names, obj["metadata"].(map[string]interface{})["name"].(string))
+	}
+	assert.Equal(t, []string{
+		"target-cluster-ca",
+		"target-cluster-etcd",
+		"target-cluster-proxy",
+		"target-cluster-sa",
+		"target-cluster-admin",
+	}, names)
+
+	// credentials are kept
+	loaded, err := pki.NewClusterPKI("target-cluster", secrets)
+	require.NoError(t, err)
+	assert.Equal(t, p.CA.Certificate.Raw, loaded.CA.Certificate.Raw)
+	assert.Equal(t, secret.EncodePrivateKey(p.ServiceAccountKey), secret.EncodePrivateKey(loaded.ServiceAccountKey))
+	assert.Equal(t, p.Admin.Certificate.Raw, loaded.Admin.Certificate.Raw)
+
+	// admin certificate is regenerated along with the authority
+	loaded, err = pki.NewClusterPKI("target-cluster", secrets[1:])
+	require.NoError(t, err)
+	assert.NotEqual(t, p.CA.Certificate.Raw, loaded.CA.Certificate.Raw)
+	assert.NotEqual(t, p.Admin.Certificate.Raw, loaded.Admin.Certificate.Raw)
+	assert.Equal(t, p.EtcdCA.Certificate.Raw, loaded.EtcdCA.Certificate.Raw)
+}
+
+func TestAddToConfig(t *testing.T) {
+	conf, cleanup := testutil.InitConfig(t)
+	defer cleanup(t)
+
+	p, err := pki.NewClusterPKI("target-cluster", nil)
+	require.NoError(t, err)
+	require.NoError(t, p.AddToConfig(conf, config.Target, "https://10.23.25.101:6443"))
+
+	cluster, err := conf.GetCluster("target-cluster", config.Target)
+	require.NoError(t, err)
+	assert.Equal(t, "https://10.23.25.101:6443", cluster.KubeCluster().Server)
+	assert.Equal(t, p.CA.CertificatePEM(), cluster.KubeCluster().CertificateAuthorityData)
+
+	authInfo, err := conf.GetAuthInfo(p.AdminName())
+	require.NoError(t, err)
+	assert.Equal(t, p.Admin.CertificatePEM(), authInfo.KubeAuthInfo().ClientCertificateData)
+	assert.Equal(t, secret.EncodePrivateKey(p.Admin.Key), authInfo.KubeAuthInfo().ClientKeyData)
+
+	// existing entries are updated
+	p, err = pki.NewClusterPKI("target-cluster", nil)
+	require.NoError(t, err)
+	require.NoError(t, p.AddToConfig(conf, config.Target, ""))
+	assert.Equal(t, "https://10.23.25.101:6443", cluster.KubeCluster().Server)
+	assert.Equal(t, p.CA.CertificatePEM(), cluster.KubeCluster().CertificateAuthorityData)
+	assert.Equal(t, p.Admin.CertificatePEM(), authInfo.KubeAuthInfo().ClientCertificateData)
+}
@@ -0,0 +1,30 @@
+package plan
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrInvalidRange returned if the first phase of the plan follows the last one
+type ErrInvalidRange struct {
+	From  string
+	Until string
+}
+
+func (e ErrInvalidRange) Error() string {
+	return fmt.Sprintf("Phase %q runs after phase %q, nothing to run", e.From, e.Until)
+}
+
+// ErrPhaseNotReady returned if resources of the phase don't reach the ready
+// status in time
+type ErrPhaseNotReady struct {
+	Phase    string
+	Resource string
+	Status   string
+	Timeout  time.Duration
+}
+
+func (e ErrPhaseNotReady) Error() string {
+	return fmt.Sprintf("Phase %q is not ready after %s: resource %q has status %q",
+		e.Phase, e.Timeout, e.Resource, e.Status)
+}
@@ -0,0 +1,108 @@
+package plan
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+// Run applies phases in the order of their dependencies waiting for each
+// phase to become ready before the next one starts. Phases completed by
+// previous runs are skipped unless Reset is set
+func (s *Settings) Run(out io.Writer) error {
+	phases, err := s.Phases()
+	if err != nil {
+		return err
+	}
+	sorted, err := phase.Sort(phases)
+	if err != nil {
+		return err
+	}
+	planned, err := selectRange(sorted, s.From, s.Until)
+	if err != nil {
+		return err
+	}
+
+	statePath := s.statePath()
+	state := &State{}
+	if !s.Reset {
+		if state, err = LoadState(statePath); err != nil {
+			return err
+		}
+	}
+
+	// bundles of phases run so far provide status checks to later phases,
+	// which usually deploy resources of CRDs from the earlier ones
+	var bundles []document.Bundle
+	for _, p := range planned {
+		bundle, err := s.Bundle(p)
+		if err != nil {
+			return err
+		}
+		bundles = append(bundles, bundle)
+
+		if state.IsCompleted(p.Name) {
+			fmt.Fprintf(out, "Phase %s skipped, completed by previous run\n", p.Name)
+			continue
+		}
+
+		fmt.Fprintf(out, "Applying phase %s\n", p.Name)
+		if err = s.Execute(p, bundle); err != nil {
+			return err
+		}
+		if s.DryRun {
+			continue
+		}
+
+		if err = s.waitForPhase(p, bundle, bundles); err != nil {
+			return err
+		}
+		state.Complete(p.Name)
+		if err = state.Save(statePath); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Phase %s completed\n", p.Name)
+	}
+	return nil
+}
+
+// statePath returns the path of the state file
+func (s *Settings) statePath() string {
+	if s.StateFile != "" {
+		return s.StateFile
+	}
+	return filepath.Join(filepath.Dir(s.AirshipConfigPath()), StateDir, s.Config().CurrentContext+".yaml")
+}
+
+// selectRange returns sorted phases starting with from and ending with until,
+// empty names mean the first and the last phase respectively
+func selectRange(sorted []*phase.Phase, from, until string) ([]*phase.Phase, error) {
+	first, last := 0, len(sorted)-1
+	var err error
+	if from != "" {
+		if first, err = indexOf(sorted, from); err != nil {
+			return nil, err
+		}
+	}
+	if until != "" {
+		if last, err = indexOf(sorted, until); err != nil {
+			return nil, err
+		}
+	}
+	if first > last {
+		return nil, ErrInvalidRange{From: from, Until: until}
+	}
+	return sorted[first : last+1], nil
+}
+
+func indexOf(phases []*phase.Phase, name string) (int, error) {
+	for i, p := range phases {
+		if p.Name == name {
+			return i, nil
+		}
+	}
+	return 0, phase.ErrPhaseNotFound{Name: name}
+}
@@ -0,0 +1,199 @@
+package plan_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamic_fake "k8s.io/client-go/dynamic/fake"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/pkg/phase"
+	"opendev.org/airship/airshipctl/pkg/plan"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const (
+	kubeconfigPath    = "testdata/kubeconfig.yaml"
+	airshipConfigFile = "testdata/config.yaml"
+)
+
+// testExecutor records names of executed phases
+type testExecutor struct {
+	executed *[]string
+}
+
+func (e testExecutor) Execute(p *phase.Phase, _ document.Bundle) error {
+	*e.executed = append(*e.executed, p.Name)
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	var executed []string
+	phase.RegisterExecutor("test", func(*phase.Settings) (phase.Executor, error) {
+		return testExecutor{executed: &executed}, nil
+	})
+
+	tests := []struct {
+		name              string
+		completed         []string
+		from              string
+		until             string
+		reset             bool
+		dryRun            bool
+		state             string
+		expectedExecuted  []string
+		expectedCompleted []string
+		expectedOut       string
+		expectedErr       error
+	}{
+		{
+			name:              "all",
+			state:             "stable",
+			expectedExecuted:  []string{"crds", "resources", "workers"},
+			expectedCompleted: []string{"crds", "resources", "workers"},
+			expectedOut: "Applying phase crds\nPhase crds completed\n" +
+				"Applying phase resources\nPhase resources completed\n" +
+				"Applying phase workers\nPhase workers completed\n",
+		},
+		{
+			name:              "resume",
+			completed:         []string{"crds"},
+			state:             "stable",
+			expectedExecuted:  []string{"resources", "workers"},
+			expectedCompleted: []string{"crds", "resources", "workers"},
+			expectedOut: "Phase crds skipped, completed by previous run\n" +
+				"Applying phase resources\nPhase resources completed\n" +
+				"Applying phase workers\nPhase workers completed\n",
+		},
+		{
+			name:              "reset",
+			completed:         []string{"crds", "resources", "workers"},
+			reset:             true,
+			state:             "stable",
+			expectedExecuted:  []string{"crds", "resources", "workers"},
+			expectedCompleted: []string{"crds", "resources", "workers"},
+		},
+		{
+			name:              "range",
+			completed:         []string{"crds"},
+			from:              "resources",
+			until:             "resources",
+			state:             "stable",
+			expectedExecuted:  []string{"resources"},
+			expectedCompleted: []string{"crds", "resources"},
+			expectedOut:       "Applying phase resources\nPhase resources completed\n",
+		},
+		{
+			name:        "invalid-range",
+			from:        "workers",
+			until:       "crds",
+			expectedErr: plan.ErrInvalidRange{From: "workers", Until: "crds"},
+		},
+		{
+			name:        "unknown-phase",
+			until:       "controlplane",
+			expectedErr: phase.ErrPhaseNotFound{Name: "controlplane"},
+		},
+		{
+			name:             "dry-run",
+			dryRun:           true,
+			state:            "pending",
+			expectedExecuted: []string{"crds", "resources", "workers"},
+		},
+		{
+			name:              "not-ready",
+			state:             "pending",
+			expectedExecuted:  []string{"crds", "resources"},
+			expectedCompleted: []string{"crds"},
+			expectedErr: plan.ErrPhaseNotReady{
+				Phase:    "resources",
+				Resource: "default/test-resource",
+				Status:   "Pending",
+				Timeout:  time.Millisecond,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			executed = nil
+			dir, cleanup := testutil.TempDir(t, "airship-plan-test")
+			defer cleanup(t)
+			stateFile := filepath.Join(dir, "state.yaml")
+			if tt.completed != nil {
+				require.NoError(t, (&plan.State{Completed: tt.completed}).Save(stateFile))
+			}
+
+			settings := makeSettings(t)
+			settings.Client = makeClient(tt.state)
+			settings.DryRun = tt.dryRun
+			settings.From = tt.from
+			settings.Until = tt.until
+			settings.Reset = tt.reset
+			settings.StateFile = stateFile
+			settings.ReadyStatus = "Stable"
+			settings.Timeout = time.Millisecond
+			settings.PollInterval = time.Millisecond
+
+			out := &bytes.Buffer{}
+			err := settings.Run(out)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedExecuted, executed)
+			if tt.expectedOut != "" {
+				assert.Equal(t, tt.expectedOut, out.String())
+			}
+
+			state, err := plan.LoadState(stateFile)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCompleted, state.Completed)
+		})
+	}
+}
+
+func makeClient(state string) fake.Client {
+	resource := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Resource",
+			"metadata": map[string]interface{}{
+				"name":      "test-resource",
+				"namespace": "default",
+			},
+			"status": map[string]interface{}{
+				"state": state,
+			},
+		},
+	}
+	return fake.Client{
+		MockDynamicClient: func() dynamic.Interface {
+			return dynamic_fake.NewSimpleDynamicClient(runtime.NewScheme(), resource)
+		},
+	}
+}
+
+func makeSettings(t *testing.T) *plan.Settings {
+	t.Helper()
+	rs := &environment.AirshipCTLSettings{}
+
+	akp, err := filepath.Abs(kubeconfigPath)
+	require.NoError(t, err)
+
+	adir, err := filepath.Abs(airshipConfigFile)
+	require.NoError(t, err)
+
+	rs.SetAirshipConfigPath(adir)
+	rs.SetKubeConfigPath(akp)
+	rs.InitConfig()
+
+	return &plan.Settings{Settings: &phase.Settings{AirshipCTLSettings: rs}}
+}
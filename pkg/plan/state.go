@@ -0,0 +1,57 @@
+package plan
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+// State is a record of phases completed by previous plan runs
+type State struct {
+	Completed []string `json:"completed"`
+}
+
+// LoadState reads the state from the file, missing file means that no phases
+// are completed yet
+func LoadState(path string) (*State, error) {
+	state := &State{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return state, yaml.Unmarshal(data, state)
+}
+
+// Save writes the state to the file creating its directory if needed
+func (s *State) Save(path string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// IsCompleted tells if the phase is recorded as completed
+func (s *State) IsCompleted(name string) bool {
+	for _, completed := range s.Completed {
+		if completed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete records the phase as completed
+func (s *State) Complete(name string) {
+	if !s.IsCompleted(name) {
+		s.Completed = append(s.Completed, name)
+	}
+}
@@ -0,0 +1,42 @@
+package plan
+
+import (
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+const (
+	// DefaultReadyStatus is the status resources of a phase must reach
+	// before the next phase starts
+	DefaultReadyStatus = "Ready"
+	// DefaultTimeout limits the time to wait for resources of a phase
+	DefaultTimeout = 10 * time.Minute
+	// DefaultPollInterval is the time between status checks of a resource
+	DefaultPollInterval = 5 * time.Second
+	// StateDir is a directory next to airship config holding plan states
+	StateDir = "plans"
+)
+
+// Settings for plan commands
+type Settings struct {
+	*phase.Settings
+
+	// From is the name of the first phase to run
+	From string
+	// Until is the name of the last phase to run
+	Until string
+	// StateFile keeps names of completed phases, defaults to a file named
+	// after the current context in StateDir
+	StateFile string
+	// Reset discards completed phases recorded in the state file
+	Reset bool
+	// ReadyStatus is the status that resources with status checks must
+	// reach before the next phase starts
+	ReadyStatus string
+	// Timeout limits the time to wait for resources of each phase
+	Timeout time.Duration
+	// PollInterval is the time between status checks, defaults to
+	// DefaultPollInterval
+	PollInterval time.Duration
+}
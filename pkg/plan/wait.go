@@ -0,0 +1,90 @@
+package plan
+
+import (
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"opendev.org/airship/airshipctl/pkg/cluster"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/phase"
+)
+
+// waitForPhase blocks until every deployed resource of the phase bundle,
+// which has a status check in one of the bundles, reaches the ready status
+func (s *Settings) waitForPhase(p *phase.Phase, bundle document.Bundle, bundles []document.Bundle) error {
+	statusMap, err := cluster.NewStatusMap(crdBundle{Bundle: bundle, bundles: bundles})
+	if err != nil {
+		return err
+	}
+	kclient, err := s.KubeClient()
+	if err != nil {
+		return err
+	}
+	docs, err := bundle.Select(document.NewDeployToK8sSelector())
+	if err != nil {
+		return err
+	}
+
+	readyStatus := cluster.Status(s.ReadyStatus)
+	if readyStatus == "" {
+		readyStatus = DefaultReadyStatus
+	}
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	interval := s.PollInterval
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, doc := range docs {
+		for {
+			status, err := statusMap.GetStatusForResource(kclient, doc)
+			if _, noCheck := err.(cluster.ErrResourceNotFound); noCheck {
+				break
+			}
+			switch {
+			case apierrors.IsNotFound(err):
+				status = cluster.UnknownStatus
+			case err != nil:
+				return err
+			}
+			if status == readyStatus {
+				break
+			}
+
+			if time.Now().After(deadline) {
+				return ErrPhaseNotReady{
+					Phase:    p.Name,
+					Resource: doc.GetNamespace() + "/" + doc.GetName(),
+					Status:   string(status),
+					Timeout:  timeout,
+				}
+			}
+			time.Sleep(interval)
+		}
+	}
+	return nil
+}
+
+// crdBundle exposes CRDs of several bundles to cluster.NewStatusMap, which
+// only looks documents up by GVK
+type crdBundle struct {
+	document.Bundle
+	bundles []document.Bundle
+}
+
+func (b crdBundle) GetByGvk(group, version, kind string) ([]document.Document, error) {
+	var docs []document.Document
+	for _, bundle := range b.bundles {
+		found, err := bundle.GetByGvk(group, version, kind)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, found...)
+	}
+	return docs, nil
+}
@@ -0,0 +1,187 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	redfishClient "opendev.org/airship/go-redfish/client"
+)
+
+// AccountsPath is the path of the account collection of the Redfish AccountService
+const AccountsPath = "/redfish/v1/AccountService/Accounts"
+
+// AccountClient manages the BMC account it logs in with through the Redfish
+// AccountService. Requests are sent directly since go-redfish doesn't
+// support the AccountService
+type AccountClient struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// account is a member of the Redfish account collection
+type account struct {
+	ODataID   string `json:"@odata.id"`
+	ODataETag string `json:"@odata.etag"`
+	UserName  string `json:"UserName"`
+}
+
+// accountCollection is the Redfish account collection
+type accountCollection struct {
+	Members []account `json:"Members"`
+}
+
+// NewAccountClient returns a client managing the account of the BMC with the
+// address in the form accepted by BaseURL
+func NewAccountClient(bmcAddress string, insecure bool, useProxy bool, username string,
+	password string) (*AccountClient, error) {
+	if bmcAddress == "" {
+		return nil, ErrRedfishMissingConfig{What: "Redfish URL"}
+	}
+	baseURL, err := BaseURL(bmcAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountClient{
+		baseURL:    baseURL,
+		username:   username,
+		password:   password,
+		httpClient: newHTTPClient(insecure, useProxy),
+	}, nil
+}
+
+// ChangePassword sets the password of the account of the client
+func (c *AccountClient) ChangePassword(ctx context.Context, newPassword string) error {
+	acc, err := c.findAccount(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]string{"Password": newPassword})
+	if err != nil {
+		return err
+	}
+	req, err := c.newRequest(ctx, http.MethodPatch, acc.ODataID, c.password, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// some BMCs reject updates without the entity tag of the account
+	if acc.ODataETag != "" {
+		req.Header.Set("If-Match", acc.ODataETag)
+	}
+	_, err = c.do(req, nil)
+	return err
+}
+
+// VerifyLogin checks that the BMC accepts the password of the account of
+// the client
+func (c *AccountClient) VerifyLogin(ctx context.Context, password string) error {
+	req, err := c.newRequest(ctx, http.MethodGet, AccountsPath, password, nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(req, nil)
+	return err
+}
+
+// findAccount returns the account of the client from the account collection
+func (c *AccountClient) findAccount(ctx context.Context) (*account, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, AccountsPath, c.password, nil)
+	if err != nil {
+		return nil, err
+	}
+	collection := &accountCollection{}
+	if _, err = c.do(req, collection); err != nil {
+		return nil, err
+	}
+
+	for _, member := range collection.Members {
+		req, err := c.newRequest(ctx, http.MethodGet, member.ODataID, c.password, nil)
+		if err != nil {
+			return nil, err
+		}
+		acc := &account{}
+		resp, err := c.do(req, acc)
+		if err != nil {
+			return nil, err
+		}
+		if acc.UserName != c.username {
+			continue
+		}
+		if acc.ODataID == "" {
+			acc.ODataID = member.ODataID
+		}
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			acc.ODataETag = etag
+		}
+		return acc, nil
+	}
+	return nil, ErrRedfishClient{Message: fmt.Sprintf("account %q is not found", c.username)}
+}
+
+func (c *AccountClient) newRequest(ctx context.Context, method, path, password string,
+	body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.SetBasicAuth(c.username, password)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "airshipctl/client")
+	return req, nil
+}
+
+// do sends the request and decodes the response body into the result if
+// it is not nil
+func (c *AccountClient) do(req *http.Request, result interface{}) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, ErrRedfishClient{Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, ErrRedfishClient{Message: err.Error()}
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode > http.StatusNoContent {
+		message := http.StatusText(resp.StatusCode)
+		var redfishErr redfishClient.RedfishError
+		if json.Unmarshal(body, &redfishErr) == nil && redfishErr.Error.Message != "" {
+			message = redfishErr.Error.Message
+		}
+		return nil, ErrRedfishClient{
+			Message: fmt.Sprintf("%s %s: %s", req.Method, strings.TrimPrefix(req.URL.Path, "/"), message),
+		}
+	}
+
+	if result != nil && len(body) > 0 {
+		if err = json.Unmarshal(body, result); err != nil {
+			return nil, ErrRedfishClient{Message: err.Error()}
+		}
+	}
+	return resp, nil
+}
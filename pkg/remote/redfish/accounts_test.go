@@ -0,0 +1,163 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+)
+
+const (
+	accountUsername = "root"
+	accountETag     = `W/"1"`
+)
+
+// fakeAccountService is a Redfish server with the accounts of the
+// AccountService only
+type fakeAccountService struct {
+	password string
+	patched  bool
+}
+
+func (s *fakeAccountService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the service doesn't tell users apart to get to the account lookup
+	_, password, ok := r.BasicAuth()
+	if !ok || password != s.password {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == redfish.AccountsPath:
+		writeJSON(w, map[string]interface{}{
+			"Members": []map[string]string{
+				{"@odata.id": redfish.AccountsPath + "/1"},
+				{"@odata.id": redfish.AccountsPath + "/2"},
+			},
+		})
+	case r.Method == http.MethodGet && r.URL.Path == redfish.AccountsPath+"/1":
+		writeJSON(w, map[string]string{"@odata.id": r.URL.Path, "UserName": "operator"})
+	case r.Method == http.MethodGet && r.URL.Path == redfish.AccountsPath+"/2":
+		w.Header().Set("ETag", accountETag)
+		writeJSON(w, map[string]string{"@odata.id": r.URL.Path, "UserName": accountUsername})
+	case r.Method == http.MethodPatch && r.URL.Path == redfish.AccountsPath+"/2":
+		if r.Header.Get("If-Match") != accountETag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body := map[string]string{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["Password"] == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]interface{}{
+				"error": map[string]string{"message": "Password is required"},
+			})
+			return
+		}
+		s.password = body["Password"]
+		s.patched = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	// encoding of the test data never fails
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newAccountClient(t *testing.T, url, username, password string) *redfish.AccountClient {
+	t.Helper()
+	client, err := redfish.NewAccountClient(
+		strings.Replace(url, "http://", "redfish+http://", 1)+"/redfish/v1/Systems/node01",
+		false, false, username, password)
+	require.NoError(t, err)
+	return client
+}
+
+func TestAccountClientChangePassword(t *testing.T) {
+	service := &fakeAccountService{password: "old"}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	client := newAccountClient(t, server.URL, accountUsername, "old")
+	require.NoError(t, client.ChangePassword(context.Background(), "new"))
+	assert.True(t, service.patched)
+	assert.Equal(t, "new", service.password)
+
+	assert.NoError(t, client.VerifyLogin(context.Background(), "new"))
+	err := client.VerifyLogin(context.Background(), "old")
+	assert.IsType(t, redfish.ErrRedfishClient{}, err)
+}
+
+func TestAccountClientChangePasswordErrors(t *testing.T) {
+	service := &fakeAccountService{password: "old"}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		username    string
+		password    string
+		newPassword string
+		expectedErr string
+	}{
+		{
+			name:        "unknown-account",
+			username:    "nobody",
+			password:    "old",
+			newPassword: "new",
+			expectedErr: `account "nobody" is not found`,
+		},
+		{
+			name:        "wrong-password",
+			username:    accountUsername,
+			password:    "wrong",
+			newPassword: "new",
+			expectedErr: "Unauthorized",
+		},
+		{
+			name:        "rejected-password",
+			username:    accountUsername,
+			password:    "old",
+			newPassword: "",
+			expectedErr: "Password is required",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			client := newAccountClient(t, server.URL, tt.username, tt.password)
+			err := client.ChangePassword(context.Background(), tt.newPassword)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+			assert.False(t, service.patched)
+		})
+	}
+}
+
+func TestNewAccountClientMissingAddress(t *testing.T) {
+	_, err := redfish.NewAccountClient("", false, false, accountUsername, "old")
+	assert.Equal(t, redfish.ErrRedfishMissingConfig{What: "Redfish URL"}, err)
+}
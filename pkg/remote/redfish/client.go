@@ -199,6 +199,20 @@ func NewClient(ephemeralNodeID string,
 		DefaultHeader: make(map[string]string),
 		UserAgent:     "airshipctl/client",
 	}
+	cfg.HTTPClient = newHTTPClient(insecure, useProxy)
+
+	c := &Client{
+		ephemeralNodeID: ephemeralNodeID,
+		isoPath:         isoPath,
+		redfishURL:      *parsedURL,
+		redfishAPI:      redfishClient.NewAPIClient(cfg).DefaultApi,
+	}
+
+	return ctx, c, nil
+}
+
+// newHTTPClient returns HTTP client for requests to Redfish servers
+func newHTTPClient(insecure bool, useProxy bool) *http.Client {
 	// see https://github.com/golang/go/issues/26013
 	// We clone the default transport to ensure when we customize the transport
 	// that we are providing it sane timeouts and other defaults that we would
@@ -216,16 +230,7 @@ func NewClient(ephemeralNodeID string,
 		transport.Proxy = nil
 	}
 
-	cfg.HTTPClient = &http.Client{
+	return &http.Client{
 		Transport: transport,
 	}
-
-	c := &Client{
-		ephemeralNodeID: ephemeralNodeID,
-		isoPath:         isoPath,
-		redfishURL:      *parsedURL,
-		redfishAPI:      redfishClient.NewAPIClient(cfg).DefaultApi,
-	}
-
-	return ctx, c, nil
 }
@@ -3,6 +3,7 @@ package redfish
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
@@ -33,6 +34,23 @@ func GetResourceIDFromURL(refURL string) string {
 	return id
 }
 
+// BaseURL returns the URL of the Redfish server of the BMC address, such as
+// redfish+https://10.23.25.1/redfish/v1/Systems/node01 which includes the
+// client type in the scheme and the system in the path
+func BaseURL(bmcAddress string) (string, error) {
+	u, err := url.Parse(bmcAddress)
+	if err != nil {
+		return "", err
+	}
+
+	scheme := u.Scheme
+	schemeSplit := strings.Split(scheme, RedfishURLSchemeSeparator)
+	if len(schemeSplit) > 1 {
+		scheme = schemeSplit[len(schemeSplit)-1]
+	}
+	return fmt.Sprintf("%s://%s", scheme, u.Host), nil
+}
+
 // Checks whether an ID exists in Redfish IDref collection
 func IsIDInList(idRefList []redfishClient.IdRef, id string) bool {
 	for _, r := range idRefList {
@@ -14,7 +14,6 @@ package remote
 
 import (
 	"context"
-	"fmt"
 	"net/url"
 	"strings"
 
@@ -50,10 +49,9 @@ func (a *Adapter) configureClient(remoteURL string) error {
 			return err
 		}
 
-		baseURL := fmt.Sprintf("%s://%s", rfURL.Scheme, rfURL.Host)
-		schemeSplit := strings.Split(rfURL.Scheme, redfish.RedfishURLSchemeSeparator)
-		if len(schemeSplit) > 1 {
-			baseURL = fmt.Sprintf("%s://%s", schemeSplit[len(schemeSplit)-1], rfURL.Host)
+		baseURL, err := redfish.BaseURL(remoteURL)
+		if err != nil {
+			return err
 		}
 
 		urlPath := strings.Split(rfURL.Path, "/")
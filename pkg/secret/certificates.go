@@ -0,0 +1,172 @@
+package secret
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math"
+	"math/big"
+	"net"
+	"time"
+)
+
+const (
+	// DefaultKeyBits is the size of RSA keys generated if the size is not set
+	DefaultKeyBits = 2048
+	// DefaultCAValidity is the validity period of certificate authorities
+	// generated if the period is not set
+	DefaultCAValidity = 10 * 365 * 24 * time.Hour
+	// DefaultCertificateValidity is the validity period of certificates
+	// generated if the period is not set
+	DefaultCertificateValidity = 365 * 24 * time.Hour
+
+	certificatePEMType   = "CERTIFICATE"
+	rsaPrivateKeyPEMType = "RSA PRIVATE KEY"
+)
+
+// KeyPair is a certificate along with its private key
+type KeyPair struct {
+	Certificate *x509.Certificate
+	Key         *rsa.PrivateKey
+}
+
+// CertificateConfig holds settings of generated certificates
+type CertificateConfig struct {
+	CommonName   string
+	Organization []string
+	// Hosts are DNS names and IP addresses the certificate is valid for
+	Hosts []string
+	// Usages are extended key usages of the certificate
+	Usages []x509.ExtKeyUsage
+	// Bits is the size of the RSA key, DefaultKeyBits if not set
+	Bits int
+	// Validity is the validity period of the certificate,
+	// DefaultCertificateValidity if not set
+	Validity time.Duration
+}
+
+// NewCertificateAuthority returns a self-signed certificate authority
+func NewCertificateAuthority(config CertificateConfig) (*KeyPair, error) {
+	if config.Validity == 0 {
+		config.Validity = DefaultCAValidity
+	}
+	template, err := certificateTemplate(config)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+
+	key, err := rsa.GenerateKey(rand.Reader, keyBits(config.Bits))
+	if err != nil {
+		return nil, err
+	}
+	return newKeyPair(template, template, key, key)
+}
+
+// NewCertificate returns a certificate signed by the certificate authority
+func (ca *KeyPair) NewCertificate(config CertificateConfig) (*KeyPair, error) {
+	if config.Validity == 0 {
+		config.Validity = DefaultCertificateValidity
+	}
+	template, err := certificateTemplate(config)
+	if err != nil {
+		return nil, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+
+	key, err := rsa.GenerateKey(rand.Reader, keyBits(config.Bits))
+	if err != nil {
+		return nil, err
+	}
+	return newKeyPair(template, ca.Certificate, key, ca.Key)
+}
+
+// CertificatePEM returns PEM encoded certificate
+func (kp *KeyPair) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: certificatePEMType, Bytes: kp.Certificate.Raw})
+}
+
+// KeyPEM returns PEM encoded private key
+func (kp *KeyPair) KeyPEM() []byte {
+	return EncodePrivateKey(kp.Key)
+}
+
+// ParseKeyPair returns KeyPair from PEM encoded certificate and private key
+func ParseKeyPair(certPEM, keyPEM []byte) (*KeyPair, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil || certBlock.Type != certificatePEMType {
+		return nil, ErrMalformedKeyPair{}
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{Certificate: cert, Key: key}, nil
+}
+
+// EncodePrivateKey returns PEM encoded RSA private key
+func EncodePrivateKey(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: rsaPrivateKeyPEMType, Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// ParsePrivateKey returns RSA private key from PEM encoded data
+func ParsePrivateKey(keyPEM []byte) (*rsa.PrivateKey, error) {
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil || keyBlock.Type != rsaPrivateKeyPEMType {
+		return nil, ErrMalformedKeyPair{}
+	}
+	return x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+}
+
+func newKeyPair(template, parent *x509.Certificate, key, parentKey *rsa.PrivateKey) (*KeyPair, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{Certificate: cert, Key: key}, nil
+}
+
+func certificateTemplate(config CertificateConfig) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   config.CommonName,
+			Organization: config.Organization,
+		},
+		NotBefore:   now.Add(-time.Minute),
+		NotAfter:    now.Add(config.Validity),
+		ExtKeyUsage: config.Usages,
+	}
+	for _, host := range config.Hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	return template, nil
+}
+
+func keyBits(bits int) int {
+	if bits == 0 {
+		return DefaultKeyBits
+	}
+	return bits
+}
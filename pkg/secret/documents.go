@@ -0,0 +1,248 @@
+package secret
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"os"
+
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// EncryptionSettings for secret encrypt and decrypt commands
+type EncryptionSettings struct {
+	// Src is the file with documents to encrypt or decrypt
+	Src string
+	// Dst is the file to write documents to, documents are written to the
+	// writer passed to Encrypt or Decrypt if it is empty. Dst may be the
+	// same file as Src
+	Dst string
+}
+
+// Encrypt encrypts values of Secret documents, other documents are written
+// as is
+func (s *EncryptionSettings) Encrypt(out io.Writer) error {
+	return s.process(out, (*Cipher).EncryptSecret)
+}
+
+// Decrypt decrypts values of Secret documents, other documents are written
+// as is
+func (s *EncryptionSettings) Decrypt(out io.Writer) error {
+	return s.process(out, (*Cipher).DecryptSecret)
+}
+
+func (s *EncryptionSettings) process(out io.Writer, transform func(*Cipher, map[string]interface{}) error) error {
+	data, err := ioutil.ReadFile(s.Src)
+	if err != nil {
+		return err
+	}
+	key, err := SiteKey()
+	if err != nil {
+		return err
+	}
+	c, err := NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	err = TransformSecrets(bytes.NewReader(data), buf, func(obj map[string]interface{}) error {
+		return transform(c, obj)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.Dst == "" {
+		_, err = out.Write(buf.Bytes())
+		return err
+	}
+	return ioutil.WriteFile(s.Dst, buf.Bytes(), 0600)
+}
+
+// TransformSecrets reads YAML documents from in, applies the transform to
+// Secret documents and writes all documents to out. Documents other than
+// secrets are written without changes
+func TransformSecrets(in io.Reader, out io.Writer, transform func(map[string]interface{}) error) error {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(in))
+	for {
+		data, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		obj := map[string]interface{}{}
+		if err = yaml.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		if len(obj) == 0 {
+			continue
+		}
+
+		if IsSecret(obj) {
+			if err = transform(obj); err != nil {
+				return err
+			}
+			if data, err = yaml.Marshal(obj); err != nil {
+				return err
+			}
+		}
+
+		if _, err = io.WriteString(out, "---\n"); err != nil {
+			return err
+		}
+		if _, err = out.Write(bytes.TrimLeft(data, "\n")); err != nil {
+			return err
+		}
+		if !bytes.HasSuffix(data, []byte("\n")) {
+			if _, err = io.WriteString(out, "\n"); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ReadSecrets returns Secret documents of the file decrypting their values if
+// needed, no documents are returned if the path is empty or the file doesn't
+// exist
+func ReadSecrets(path string) ([]map[string]interface{}, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c *Cipher
+	var secrets []map[string]interface{}
+	err = TransformSecrets(bytes.NewReader(data), ioutil.Discard, func(obj map[string]interface{}) error {
+		if HasEncryptedValues(obj) {
+			if c == nil {
+				key, err := SiteKey()
+				if err != nil {
+					return err
+				}
+				if c, err = NewCipher(key); err != nil {
+					return err
+				}
+			}
+			if err := c.DecryptSecret(obj); err != nil {
+				return err
+			}
+		}
+		secrets = append(secrets, obj)
+		return nil
+	})
+	return secrets, err
+}
+
+// WriteSecrets writes Secret documents to the dst file or to out if dst is
+// empty, values are encrypted with the site key if encrypt is set. Values
+// which are the same as in the dst file keep their encrypted form, so that
+// unchanged secrets are not changed in the file
+func WriteSecrets(out io.Writer, dst string, secrets []map[string]interface{}, encrypt bool) error {
+	if encrypt {
+		key, err := SiteKey()
+		if err != nil {
+			return err
+		}
+		c, err := NewCipher(key)
+		if err != nil {
+			return err
+		}
+		previous, err := encryptedValues(dst)
+		if err != nil {
+			return err
+		}
+		for _, obj := range secrets {
+			if err = c.encryptSecret(obj, previous); err != nil {
+				return err
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	for _, obj := range secrets {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+
+	if dst == "" {
+		_, err := out.Write(buf.Bytes())
+		return err
+	}
+	return ioutil.WriteFile(dst, buf.Bytes(), 0600)
+}
+
+// encryptedValues returns encrypted values of Secret documents of the file
+// by their associated data, no values are returned if the path is empty or
+// the file doesn't exist
+func encryptedValues(path string) (map[string][]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string][]string)
+	err = TransformSecrets(bytes.NewReader(data), ioutil.Discard, func(obj map[string]interface{}) error {
+		return transformSecret(obj, func(key, value string) (string, error) {
+			if IsEncrypted(value) {
+				associatedData := AssociatedData(obj, key)
+				values[associatedData] = append(values[associatedData], value)
+			}
+			return value, nil
+		})
+	})
+	return values, err
+}
+
+// NewSecret returns Secret object with the data encoded
+func NewSecret(name, namespace, secretType string, data map[string][]byte) map[string]interface{} {
+	metadata := map[string]interface{}{"name": name}
+	if namespace != "" {
+		metadata["namespace"] = namespace
+	}
+	encoded := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		encoded[key] = base64.StdEncoding.EncodeToString(value)
+	}
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   metadata,
+		"type":       secretType,
+		"data":       encoded,
+	}
+}
+
+// SecretData returns decoded value of data of the Secret object, nil is
+// returned if the value is missing or malformed
+func SecretData(obj map[string]interface{}, key string) []byte {
+	data, _ := obj["data"].(map[string]interface{})
+	value, _ := data[key].(string)
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || value == "" {
+		return nil
+	}
+	return decoded
+}
@@ -0,0 +1,268 @@
+package secret
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	crypto "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+const (
+	// SiteKeyEnv is the environment variable holding the site key
+	SiteKeyEnv = "AIRSHIP_SITE_KEY"
+	// SiteKeyFileEnv is the environment variable holding the path of the
+	// file with the site key
+	SiteKeyFileEnv = "AIRSHIP_SITE_KEY_FILE"
+	// SiteKeyFile is the name of the site key file in the airship config
+	// directory, used if neither of the environment variables is set
+	SiteKeyFile = "site.key"
+
+	encryptedPrefix = "ENC[AES256_GCM,"
+	encryptedSuffix = "]"
+
+	saltSize      = 16
+	keySize       = 32
+	keyIterations = 100000
+
+	defaultNamespace = "default"
+)
+
+// secretDataFields are fields of Secret documents holding secret values
+var secretDataFields = []string{"data", "stringData"}
+
+// SiteKey returns the site key from SiteKeyEnv, from the file referenced by
+// SiteKeyFileEnv or from SiteKeyFile in the airship config directory
+func SiteKey() (string, error) {
+	if key := os.Getenv(SiteKeyEnv); key != "" {
+		return key, nil
+	}
+
+	keyFile := os.Getenv(SiteKeyFileEnv)
+	if keyFile == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		keyFile = filepath.Join(homeDir, config.AirshipConfigDir, SiteKeyFile)
+	}
+
+	data, err := ioutil.ReadFile(keyFile)
+	if os.IsNotExist(err) {
+		return "", ErrSiteKeyNotFound{KeyFile: keyFile}
+	}
+	if err != nil {
+		return "", err
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", ErrSiteKeyNotFound{KeyFile: keyFile}
+	}
+	return key, nil
+}
+
+// Cipher encrypts and decrypts secret values using AES-256-GCM with keys
+// derived from the site key. Values encrypted by the same Cipher share the
+// key derivation salt, which is stored along with every value
+type Cipher struct {
+	siteKey []byte
+	salt    []byte
+	aeads   map[string]cipher.AEAD
+}
+
+// NewCipher returns Cipher for the site key
+func NewCipher(siteKey string) (*Cipher, error) {
+	if siteKey == "" {
+		return nil, ErrSiteKeyNotFound{}
+	}
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(crypto.Reader, salt); err != nil {
+		return nil, err
+	}
+	return &Cipher{
+		siteKey: []byte(siteKey),
+		salt:    salt,
+		aeads:   make(map[string]cipher.AEAD),
+	}, nil
+}
+
+// IsEncrypted tells if the value is encrypted by Cipher
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix) && strings.HasSuffix(value, encryptedSuffix)
+}
+
+// ContainsEncryptedValues tells if the data, such as a file with documents,
+// holds values encrypted by Cipher
+func ContainsEncryptedValues(data []byte) bool {
+	return bytes.Contains(data, []byte(encryptedPrefix))
+}
+
+// Encrypt returns encrypted value in the form of ENC[AES256_GCM,<base64>],
+// the value is authenticated along with the associated data, which must be
+// the same to decrypt it
+func (c *Cipher) Encrypt(plaintext, associatedData string) (string, error) {
+	aead, err := c.aead(c.salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(crypto.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := append(append([]byte{}, c.salt...), nonce...)
+	sealed = aead.Seal(sealed, nonce, []byte(plaintext), []byte(associatedData))
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed) + encryptedSuffix, nil
+}
+
+// Decrypt returns plain text of the value encrypted by Encrypt with the same
+// associated data
+func (c *Cipher) Decrypt(value, associatedData string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", ErrMalformedValue{}
+	}
+	sealed, err := base64.StdEncoding.DecodeString(
+		strings.TrimSuffix(strings.TrimPrefix(value, encryptedPrefix), encryptedSuffix))
+	if err != nil || len(sealed) < saltSize {
+		return "", ErrMalformedValue{}
+	}
+
+	aead, err := c.aead(sealed[:saltSize])
+	if err != nil {
+		return "", err
+	}
+	sealed = sealed[saltSize:]
+	if len(sealed) < aead.NonceSize() {
+		return "", ErrMalformedValue{}
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(associatedData))
+	if err != nil {
+		return "", ErrDecryptionFailed{}
+	}
+	return string(plaintext), nil
+}
+
+// aead returns AES-GCM cipher with the key derived from the site key and
+// the salt, derived keys are cached since the derivation is slow on purpose
+func (c *Cipher) aead(salt []byte) (cipher.AEAD, error) {
+	if aead, found := c.aeads[string(salt)]; found {
+		return aead, nil
+	}
+	key := pbkdf2.Key(c.siteKey, salt, keyIterations, keySize, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	c.aeads[string(salt)] = aead
+	return aead, nil
+}
+
+// EncryptSecret encrypts values of data and stringData of the Secret object,
+// values which are already encrypted are left as is. Values are bound to the
+// namespace and the name of the Secret and their keys, so that they can't be
+// moved to another Secret or key
+func (c *Cipher) EncryptSecret(obj map[string]interface{}) error {
+	return c.encryptSecret(obj, nil)
+}
+
+// encryptSecret encrypts values of the Secret object, previous encrypted
+// values with the same associated data are used instead if they decrypt to
+// the same value, so that unchanged values are not encrypted again
+func (c *Cipher) encryptSecret(obj map[string]interface{}, previous map[string][]string) error {
+	return transformSecret(obj, func(key, value string) (string, error) {
+		if IsEncrypted(value) {
+			return value, nil
+		}
+		associatedData := AssociatedData(obj, key)
+		for _, encrypted := range previous[associatedData] {
+			// values encrypted with another site key are encrypted again
+			if decrypted, err := c.Decrypt(encrypted, associatedData); err == nil && decrypted == value {
+				return encrypted, nil
+			}
+		}
+		return c.Encrypt(value, associatedData)
+	})
+}
+
+// DecryptSecret decrypts encrypted values of data and stringData of the
+// Secret object
+func (c *Cipher) DecryptSecret(obj map[string]interface{}) error {
+	return transformSecret(obj, func(key, value string) (string, error) {
+		if !IsEncrypted(value) {
+			return value, nil
+		}
+		return c.Decrypt(value, AssociatedData(obj, key))
+	})
+}
+
+// AssociatedData returns the data values of the Secret object are encrypted
+// with, in the form of <namespace>/<name>/<key>. Secrets without namespace
+// are in the default namespace
+func AssociatedData(obj map[string]interface{}, key string) string {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	namespace, _ := metadata["namespace"].(string)
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return namespace + "/" + name + "/" + key
+}
+
+// HasEncryptedValues tells if the Secret object has encrypted values
+func HasEncryptedValues(obj map[string]interface{}) bool {
+	found := false
+	// the function never fails, so the error is ignored
+	_ = transformSecret(obj, func(_, value string) (string, error) {
+		found = found || IsEncrypted(value)
+		return value, nil
+	})
+	return found
+}
+
+// IsSecret tells if the object is a Secret
+func IsSecret(obj map[string]interface{}) bool {
+	return obj["apiVersion"] == "v1" && obj["kind"] == "Secret"
+}
+
+func transformSecret(obj map[string]interface{}, transform func(key, value string) (string, error)) error {
+	for _, field := range secretDataFields {
+		data, ok := obj[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			value, ok := data[key].(string)
+			if !ok {
+				continue
+			}
+			transformed, err := transform(key, value)
+			if err != nil {
+				return ErrSecretValue{Field: field + "." + key, Err: err}
+			}
+			data[key] = transformed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package secret_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/secret"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const testSiteKey = "test-site-key"
+
+func TestCipher(t *testing.T) {
+	c, err := secret.NewCipher(testSiteKey)
+	require.NoError(t, err)
+
+	encrypted, err := c.Encrypt("passw0rd", "default/bmc/password")
+	require.NoError(t, err)
+	assert.True(t, secret.IsEncrypted(encrypted))
+	assert.NotContains(t, encrypted, "passw0rd")
+
+	// another cipher with the same key decrypts values with their own salt
+	other, err := secret.NewCipher(testSiteKey)
+	require.NoError(t, err)
+	decrypted, err := other.Decrypt(encrypted, "default/bmc/password")
+	require.NoError(t, err)
+	assert.Equal(t, "passw0rd", decrypted)
+
+	// values can't be decrypted with different associated data
+	_, err = other.Decrypt(encrypted, "default/other/password")
+	assert.Equal(t, secret.ErrDecryptionFailed{}, err)
+
+	wrongKey, err := secret.NewCipher("wrong-key")
+	require.NoError(t, err)
+	_, err = wrongKey.Decrypt(encrypted, "default/bmc/password")
+	assert.Equal(t, secret.ErrDecryptionFailed{}, err)
+
+	_, err = c.Decrypt("ENC[AES256_GCM,bm90IGVub3VnaA==]", "")
+	assert.Equal(t, secret.ErrMalformedValue{}, err)
+
+	_, err = secret.NewCipher("")
+	assert.Equal(t, secret.ErrSiteKeyNotFound{}, err)
+}
+
+func TestEncryptSecret(t *testing.T) {
+	c, err := secret.NewCipher(testSiteKey)
+	require.NoError(t, err)
+
+	obj := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "bmc"},
+		"data":       map[string]interface{}{"password": "cGFzc3cwcmQ="},
+		"stringData": map[string]interface{}{"username": "admin"},
+	}
+	require.True(t, secret.IsSecret(obj))
+	assert.False(t, secret.HasEncryptedValues(obj))
+
+	require.NoError(t, c.EncryptSecret(obj))
+	assert.True(t, secret.HasEncryptedValues(obj))
+	encrypted := obj["data"].(map[string]interface{})["password"]
+
+	// encrypted values are not encrypted twice
+	require.NoError(t, c.EncryptSecret(obj))
+	assert.Equal(t, encrypted, obj["data"].(map[string]interface{})["password"])
+
+	// encrypted values can't be moved to another Secret
+	moved := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "other"},
+		"data":       map[string]interface{}{"password": encrypted},
+	}
+	assert.Error(t, c.DecryptSecret(moved))
+
+	require.NoError(t, c.DecryptSecret(obj))
+	assert.False(t, secret.HasEncryptedValues(obj))
+	assert.Equal(t, "cGFzc3cwcmQ=", obj["data"].(map[string]interface{})["password"])
+	assert.Equal(t, "admin", obj["stringData"].(map[string]interface{})["username"])
+}
+
+func TestSiteKey(t *testing.T) {
+	tempDir, cleanup := testutil.TempDir(t, "airship-site-key")
+	defer cleanup(t)
+	keyFile := filepath.Join(tempDir, secret.SiteKeyFile)
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte(testSiteKey+"\n"), 0600))
+
+	defer testutil.SetEnv(t, secret.SiteKeyEnv, "")()
+	defer testutil.SetEnv(t, secret.SiteKeyFileEnv, keyFile)()
+
+	key, err := secret.SiteKey()
+	require.NoError(t, err)
+	assert.Equal(t, testSiteKey, key)
+
+	require.NoError(t, os.Setenv(secret.SiteKeyEnv, "env-site-key"))
+	key, err = secret.SiteKey()
+	require.NoError(t, err)
+	assert.Equal(t, "env-site-key", key)
+
+	require.NoError(t, os.Setenv(secret.SiteKeyEnv, ""))
+	missingFile := filepath.Join(tempDir, "missing.key")
+	require.NoError(t, os.Setenv(secret.SiteKeyFileEnv, missingFile))
+	_, err = secret.SiteKey()
+	assert.Equal(t, secret.ErrSiteKeyNotFound{KeyFile: missingFile}, err)
+}
+
+func TestEncryptionSettings(t *testing.T) {
+	tempDir, cleanup := testutil.TempDir(t, "airship-secrets")
+	defer cleanup(t)
+	defer testutil.SetEnv(t, secret.SiteKeyEnv, testSiteKey)()
+
+	encrypted := filepath.Join(tempDir, "secrets.yaml")
+	settings := &secret.EncryptionSettings{Src: "testdata/secrets.yaml", Dst: encrypted}
+	require.NoError(t, settings.Encrypt(nil))
+
+	data, err := ioutil.ReadFile(encrypted)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "cGFzc3cwcmQ=")
+	assert.Contains(t, string(data), "hostname: node01")
+	assert.Equal(t, 2, strings.Count(string(data), "ENC[AES256_GCM,"))
+
+	out := &bytes.Buffer{}
+	settings = &secret.EncryptionSettings{Src: encrypted}
+	require.NoError(t, settings.Decrypt(out))
+
+	expected, err := ioutil.ReadFile("testdata/secrets.yaml")
+	require.NoError(t, err)
+	testutil.AssertEqualDocuments(t, expected, out.Bytes())
+}
+
+func TestWriteSecrets(t *testing.T) {
+	tempDir, cleanup := testutil.TempDir(t, "airship-secrets")
+	defer cleanup(t)
+	defer testutil.SetEnv(t, secret.SiteKeyEnv, testSiteKey)()
+
+	dst := filepath.Join(tempDir, "secrets.yaml")
+	secrets := []map[string]interface{}{
+		secret.NewSecret("first", "", "Opaque", map[string][]byte{"password": []byte("first")}),
+		secret.NewSecret("second", "", "Opaque", map[string][]byte{"password": []byte("second")}),
+	}
+	require.NoError(t, secret.WriteSecrets(nil, dst, secrets, true))
+	written, err := ioutil.ReadFile(dst)
+	require.NoError(t, err)
+
+	// unchanged values keep their encrypted form, changed ones are encrypted again
+	secrets, err = secret.ReadSecrets(dst)
+	require.NoError(t, err)
+	require.NoError(t, secret.WriteSecrets(nil, dst, secrets, true))
+	rewritten, err := ioutil.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, string(written), string(rewritten))
+
+	secrets, err = secret.ReadSecrets(dst)
+	require.NoError(t, err)
+	secrets[1]["data"].(map[string]interface{})["password"] = "Y2hhbmdlZA=="
+	require.NoError(t, secret.WriteSecrets(nil, dst, secrets, true))
+	rewritten, err = ioutil.ReadFile(dst)
+	require.NoError(t, err)
+	writtenDocs := strings.Split(string(written), "---\n")
+	rewrittenDocs := strings.Split(string(rewritten), "---\n")
+	require.Len(t, rewrittenDocs, 3)
+	assert.Equal(t, writtenDocs[1], rewrittenDocs[1])
+	assert.NotEqual(t, writtenDocs[2], rewrittenDocs[2])
+
+	secrets, err = secret.ReadSecrets(dst)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("changed"), secret.SecretData(secrets[1], "password"))
+}
@@ -0,0 +1,81 @@
+package secret
+
+import (
+	"fmt"
+)
+
+// ErrSiteKeyNotFound returned if the site key is not set
+type ErrSiteKeyNotFound struct {
+	KeyFile string
+}
+
+func (e ErrSiteKeyNotFound) Error() string {
+	if e.KeyFile == "" {
+		return "Site key is empty"
+	}
+	return fmt.Sprintf("Site key is not found, set %s or %s environment variable or create %s",
+		SiteKeyEnv, SiteKeyFileEnv, e.KeyFile)
+}
+
+// ErrMalformedValue returned if the encrypted value can't be parsed
+type ErrMalformedValue struct{}
+
+func (e ErrMalformedValue) Error() string {
+	return "Encrypted value is malformed"
+}
+
+// ErrDecryptionFailed returned if the value can't be decrypted with the site key
+type ErrDecryptionFailed struct{}
+
+func (e ErrDecryptionFailed) Error() string {
+	return "Failed to decrypt value, the site key doesn't match the one used for encryption"
+}
+
+// ErrSecretValue returned if the value of Secret document can't be encrypted
+// or decrypted
+type ErrSecretValue struct {
+	Field string
+	Err   error
+}
+
+func (e ErrSecretValue) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+// ErrMalformedKeyPair returned if the certificate or the private key can't be
+// decoded
+type ErrMalformedKeyPair struct{}
+
+func (e ErrMalformedKeyPair) Error() string {
+	return "Certificate or private key is not PEM encoded"
+}
+
+// ErrDuplicateSecret returned if several secrets of site secrets documents
+// have the same name
+type ErrDuplicateSecret struct {
+	Name string
+}
+
+func (e ErrDuplicateSecret) Error() string {
+	return fmt.Sprintf("Secret %q is declared more than once", e.Name)
+}
+
+// ErrInvalidSecretSpec returned if the secret declaration is invalid
+type ErrInvalidSecretSpec struct {
+	Name string
+	Msg  string
+}
+
+func (e ErrInvalidSecretSpec) Error() string {
+	return fmt.Sprintf("Secret %q is invalid: %s", e.Name, e.Msg)
+}
+
+// ErrInvalidPassphrasePolicy returned if passphrases can't be generated with
+// the policy
+type ErrInvalidPassphrasePolicy struct {
+	Msg string
+}
+
+func (e ErrInvalidPassphrasePolicy) Error() string {
+	return fmt.Sprintf("Invalid passphrase policy: %s", e.Msg)
+}
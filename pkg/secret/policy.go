@@ -0,0 +1,210 @@
+package secret
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Character classes of passphrases
+const (
+	LowersClass  = "lowers"
+	UppersClass  = "uppers"
+	NumbersClass = "numbers"
+	SymbolsClass = "symbols"
+)
+
+const (
+	// DefaultWordCount is the number of words of word passphrases generated
+	// if the length is not set, word passphrases of the embedded wordlist
+	// need 15 words to be as strong as passphrases of 24 characters
+	DefaultWordCount = 15
+	// DefaultWordSeparator separates words of word passphrases
+	DefaultWordSeparator = "-"
+)
+
+// Classes lists character classes in the order they are checked
+var Classes = []string{LowersClass, UppersClass, NumbersClass, SymbolsClass}
+
+var classChars = map[string]string{
+	LowersClass:  asciiLowers,
+	UppersClass:  asciiUppers,
+	NumbersClass: asciiNumbers,
+	SymbolsClass: asciiSymbols,
+}
+
+// PassphrasePolicy describes passphrases generated by PassphraseEngine
+type PassphrasePolicy struct {
+	// Length is the number of characters of the passphrase or the number
+	// of words of word passphrases, 24 characters or DefaultWordCount words
+	// if not set
+	Length int `json:"length,omitempty"`
+	// Classes are character classes passphrases consist of, all classes if
+	// not set
+	Classes []string `json:"classes,omitempty"`
+	// Exclude holds characters which are never used
+	Exclude string `json:"exclude,omitempty"`
+	// MinCounts is the minimal number of characters of each class, counts
+	// of classes which are not allowed are ignored
+	MinCounts map[string]int `json:"minCounts,omitempty"`
+	// Words tells to generate diceware-style passphrases of words from the
+	// embedded wordlist instead of characters
+	Words bool `json:"words,omitempty"`
+	// Separator of words, DefaultWordSeparator if not set
+	Separator string `json:"separator,omitempty"`
+}
+
+// NewPassphrasePolicy returns the policy of passphrases generated by
+// GeneratePassphrase: 24 characters with at least one character of each class
+func NewPassphrasePolicy() *PassphrasePolicy {
+	minCounts := make(map[string]int, len(Classes))
+	for _, class := range Classes {
+		minCounts[class] = 1
+	}
+	return &PassphrasePolicy{
+		Classes:   append([]string{}, Classes...),
+		MinCounts: minCounts,
+	}
+}
+
+// Validate checks that passphrases satisfying the policy can be generated
+func (p *PassphrasePolicy) Validate() error {
+	if p.Length < 0 {
+		return ErrInvalidPassphrasePolicy{Msg: "length can't be negative"}
+	}
+	if p.Words {
+		return nil
+	}
+
+	sets, err := p.classSets()
+	if err != nil {
+		return err
+	}
+	required := 0
+	for _, class := range Classes {
+		set, allowed := sets[class]
+		if !allowed {
+			continue
+		}
+		count := p.MinCounts[class]
+		if count > 0 && set == "" {
+			return ErrInvalidPassphrasePolicy{Msg: fmt.Sprintf("all characters of class %s are excluded", class)}
+		}
+		required += count
+	}
+	if len(p.pool(sets)) == 0 {
+		return ErrInvalidPassphrasePolicy{Msg: "no characters are allowed"}
+	}
+	if required > p.length() {
+		return ErrInvalidPassphrasePolicy{
+			Msg: fmt.Sprintf("%d characters are required by classes, but the length is %d", required, p.length()),
+		}
+	}
+	return nil
+}
+
+// Entropy returns the number of bits of entropy of passphrases generated
+// with the policy. Required counts of classes are not taken into account,
+// so the value is an upper bound for character passphrases
+func (p *PassphrasePolicy) Entropy() (float64, error) {
+	if err := p.Validate(); err != nil {
+		return 0, err
+	}
+	if p.Words {
+		return float64(p.length()) * math.Log2(float64(len(wordlist))), nil
+	}
+	sets, err := p.classSets()
+	if err != nil {
+		return 0, err
+	}
+	return float64(p.length()) * math.Log2(float64(len(p.pool(sets)))), nil
+}
+
+// GeneratePolicyPassphrase returns a secure random passphrase satisfying
+// the policy
+func (e *PassphraseEngine) GeneratePolicyPassphrase(policy *PassphrasePolicy) (string, error) {
+	if err := policy.Validate(); err != nil {
+		return "", err
+	}
+
+	if policy.Words {
+		words := make([]string, policy.length())
+		for i := range words {
+			words[i] = wordlist[e.rng.Intn(len(wordlist))]
+		}
+		separator := policy.Separator
+		if separator == "" {
+			separator = DefaultWordSeparator
+		}
+		return strings.Join(words, separator), nil
+	}
+
+	sets, err := policy.classSets()
+	if err != nil {
+		return "", err
+	}
+	passphrase := make([]byte, 0, policy.length())
+	// required characters go first and are shuffled along with the rest
+	for _, class := range Classes {
+		set, allowed := sets[class]
+		if !allowed {
+			continue
+		}
+		for i := 0; i < policy.MinCounts[class]; i++ {
+			passphrase = append(passphrase, set[e.rng.Intn(len(set))])
+		}
+	}
+	pool := policy.pool(sets)
+	for len(passphrase) < policy.length() {
+		passphrase = append(passphrase, pool[e.rng.Intn(len(pool))])
+	}
+	e.rng.Shuffle(len(passphrase), func(i, j int) {
+		passphrase[i], passphrase[j] = passphrase[j], passphrase[i]
+	})
+	return string(passphrase), nil
+}
+
+func (p *PassphrasePolicy) length() int {
+	switch {
+	case p.Length > 0:
+		return p.Length
+	case p.Words:
+		return DefaultWordCount
+	default:
+		return defaultLength
+	}
+}
+
+// classSets returns characters of allowed classes without excluded ones
+func (p *PassphrasePolicy) classSets() (map[string]string, error) {
+	classes := p.Classes
+	if len(classes) == 0 {
+		classes = Classes
+	}
+
+	sets := make(map[string]string, len(classes))
+	for _, class := range classes {
+		chars, found := classChars[class]
+		if !found {
+			return nil, ErrInvalidPassphrasePolicy{
+				Msg: fmt.Sprintf("unknown character class %s, supported classes are %s", class, strings.Join(Classes, ", ")),
+			}
+		}
+		sets[class] = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(p.Exclude, r) {
+				return -1
+			}
+			return r
+		}, chars)
+	}
+	return sets, nil
+}
+
+// pool returns all characters of the sets in the order of Classes
+func (p *PassphrasePolicy) pool(sets map[string]string) string {
+	var sb strings.Builder
+	for _, class := range Classes {
+		sb.WriteString(sets[class])
+	}
+	return sb.String()
+}
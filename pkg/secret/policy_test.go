@@ -0,0 +1,116 @@
+package secret_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+func TestGeneratePolicyPassphrase(t *testing.T) {
+	engine := secret.NewPassphraseEngine(rand.NewSource(42))
+
+	policy := &secret.PassphrasePolicy{
+		Length:  16,
+		Classes: []string{secret.LowersClass, secret.NumbersClass, secret.SymbolsClass},
+		Exclude: "#&?",
+		MinCounts: map[string]int{
+			secret.NumbersClass: 3,
+			secret.SymbolsClass: 2,
+			// ignored since the class is not allowed
+			secret.UppersClass: 1,
+		},
+	}
+	for i := 0; i < 1000; i++ {
+		passphrase, err := engine.GeneratePolicyPassphrase(policy)
+		require.NoError(t, err)
+		assert.Len(t, passphrase, 16)
+		assert.False(t, strings.ContainsAny(passphrase, "#&?"+asciiUppers), passphrase)
+		assert.True(t, countAny(passphrase, asciiNumbers) >= 3, passphrase)
+		assert.True(t, countAny(passphrase, "@-+=") >= 2, passphrase)
+	}
+
+	passphrase, err := engine.GeneratePolicyPassphrase(secret.NewPassphrasePolicy())
+	require.NoError(t, err)
+	assert.Len(t, passphrase, defaultLength)
+
+	words, err := engine.GeneratePolicyPassphrase(&secret.PassphrasePolicy{Words: true, Separator: " "})
+	require.NoError(t, err)
+	assert.Len(t, strings.Fields(words), secret.DefaultWordCount)
+}
+
+func TestPassphrasePolicyEntropy(t *testing.T) {
+	entropy, err := (&secret.PassphrasePolicy{Length: 10, Classes: []string{secret.NumbersClass}}).Entropy()
+	require.NoError(t, err)
+	assert.InDelta(t, 33.2, entropy, 0.1)
+
+	entropy, err = secret.NewPassphrasePolicy().Entropy()
+	require.NoError(t, err)
+	assert.InDelta(t, 146.6, entropy, 0.1)
+
+	// default word passphrases are at least as strong as character ones
+	words, err := (&secret.PassphrasePolicy{Words: true}).Entropy()
+	require.NoError(t, err)
+	assert.True(t, words >= entropy, words)
+}
+
+func TestPassphrasePolicyValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      *secret.PassphrasePolicy
+		expectedErr string
+	}{
+		{
+			name:        "unknown-class",
+			policy:      &secret.PassphrasePolicy{Classes: []string{"emoji"}},
+			expectedErr: "unknown character class emoji",
+		},
+		{
+			name: "excluded-class",
+			policy: &secret.PassphrasePolicy{
+				Exclude:   asciiSymbols,
+				MinCounts: map[string]int{secret.SymbolsClass: 1},
+			},
+			expectedErr: "all characters of class symbols are excluded",
+		},
+		{
+			name: "too-many-required",
+			policy: &secret.PassphrasePolicy{
+				Length:    4,
+				MinCounts: map[string]int{secret.LowersClass: 3, secret.NumbersClass: 2},
+			},
+			expectedErr: "5 characters are required by classes, but the length is 4",
+		},
+		{
+			name: "nothing-allowed",
+			policy: &secret.PassphrasePolicy{
+				Classes: []string{secret.NumbersClass},
+				Exclude: asciiNumbers,
+			},
+			expectedErr: "no characters are allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func countAny(s, chars string) int {
+	count := 0
+	for _, r := range s {
+		if strings.ContainsRune(chars, r) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,395 @@
+package rotate
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+// PasswordKey is the key of the password in BMC credentials secrets
+const PasswordKey = "password"
+
+// AccountService manages the BMC account used to log in
+type AccountService interface {
+	ChangePassword(ctx context.Context, newPassword string) error
+	VerifyLogin(ctx context.Context, password string) error
+}
+
+// AccountServiceFactory returns AccountService of the BMC at the address
+// logging in with the credentials
+type AccountServiceFactory func(address, username, password string) (AccountService, error)
+
+// BMCSettings for secret rotate bmc command
+type BMCSettings struct {
+	*environment.AirshipCTLSettings
+	// ClusterType is the cluster type whose entrypoint holds BareMetalHosts
+	ClusterType string
+	// Hosts are names of BareMetalHosts to rotate passwords of
+	Hosts []string
+	// All selects all BareMetalHosts of the bundle
+	All bool
+	// SecretsFile is the manifest file holding BMC credentials secrets, it
+	// is updated once new passwords are verified
+	SecretsFile string
+	// Policy of generated passwords, secret.NewPassphrasePolicy if not set
+	Policy   *secret.PassphrasePolicy
+	Insecure bool
+	UseProxy bool
+	// NewAccountService returns the service changing passwords, the Redfish
+	// AccountService is used if not set
+	NewAccountService AccountServiceFactory
+}
+
+// credentials are BMC credentials of the Secret shared by the hosts
+type credentials struct {
+	secretName string
+	username   string
+	password   string
+	hosts      []document.Document
+}
+
+// secretsFile is the secrets file being updated, its contents are kept to
+// prepare updates before passwords are changed
+type secretsFile struct {
+	path string
+	mode os.FileMode
+	data []byte
+	// cipher encrypts passwords if the file has encrypted values
+	cipher *secret.Cipher
+}
+
+// Rotate changes BMC passwords of the selected hosts and updates their
+// credentials secrets in SecretsFile. Hosts sharing a Secret get the same
+// password, the Secret is updated only after every such host accepts it.
+// Updates of the file are prepared before any password is changed, hosts
+// get the previous password back if the file can't be written
+func (s *BMCSettings) Rotate(out io.Writer) error {
+	creds, err := s.credentials()
+	if err != nil {
+		return err
+	}
+	file, err := s.openSecretsFile(creds)
+	if err != nil {
+		return err
+	}
+
+	policy := s.Policy
+	if policy == nil {
+		policy = secret.NewPassphrasePolicy()
+	}
+	engine := secret.NewPassphraseEngine(nil)
+	ctx := context.Background()
+	for _, c := range creds {
+		password, err := engine.GeneratePolicyPassphrase(policy)
+		if err != nil {
+			return err
+		}
+		data, err := file.setPassword(c.secretName, password)
+		if err != nil {
+			return err
+		}
+		if err = s.changePassword(ctx, c, password); err != nil {
+			return err
+		}
+		if err = file.write(data); err != nil {
+			notUpdated := ErrSecretNotUpdated{Name: c.secretName, File: s.SecretsFile, Err: err}
+			notUpdated.Unrecovered = restorePassword(ctx, s.accountServiceFactory(), c.hosts, c, password)
+			if len(notUpdated.Unrecovered) > 0 {
+				notUpdated.Password = password
+				notUpdated.RecoveryFile, notUpdated.RecoveryErr = writeRecoveryFile(c.secretName, password)
+			}
+			return notUpdated
+		}
+		for _, host := range c.hosts {
+			fmt.Fprintf(out, "BMC password of host %q rotated, Secret %q updated.\n",
+				host.GetName(), c.secretName)
+		}
+	}
+	return nil
+}
+
+// credentials returns BMC credentials of the selected hosts grouped by their
+// secrets in the order hosts are selected
+func (s *BMCSettings) credentials() ([]*credentials, error) {
+	if !s.All && len(s.Hosts) == 0 {
+		return nil, ErrNoHostsSelected{}
+	}
+
+	entryPoint, err := s.Config().CurrentContextEntryPoint(s.ClusterType, "")
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := document.NewBundleByPath(entryPoint)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := bundle.Select(document.NewSelector().ByKind(document.BareMetalHostKind))
+	if err != nil {
+		return nil, err
+	}
+
+	selected := hosts
+	if !s.All {
+		byName := make(map[string]document.Document, len(hosts))
+		for _, host := range hosts {
+			byName[host.GetName()] = host
+		}
+		selected = make([]document.Document, 0, len(s.Hosts))
+		for _, name := range s.Hosts {
+			host, found := byName[name]
+			if !found {
+				return nil, ErrHostNotFound{Name: name}
+			}
+			selected = append(selected, host)
+		}
+	}
+
+	var creds []*credentials
+	bySecret := make(map[string]*credentials)
+	for _, host := range selected {
+		secretName, err := host.GetString("spec.bmc.credentialsName")
+		if err != nil {
+			return nil, err
+		}
+		if c, found := bySecret[secretName]; found {
+			c.hosts = append(c.hosts, host)
+			continue
+		}
+		username, password, err := document.GetBMHBMCCredentials(host, bundle)
+		if err != nil {
+			return nil, err
+		}
+		c := &credentials{
+			secretName: secretName,
+			username:   username,
+			password:   password,
+			hosts:      []document.Document{host},
+		}
+		bySecret[secretName] = c
+		creds = append(creds, c)
+	}
+	return creds, nil
+}
+
+// openSecretsFile makes sure the credentials can be updated before any
+// password is changed: the file has their secrets, it is writable and the
+// site key is available if the file has encrypted values
+func (s *BMCSettings) openSecretsFile(creds []*credentials) (*secretsFile, error) {
+	secrets, err := secret.ReadSecrets(s.SecretsFile)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(secrets))
+	for _, obj := range secrets {
+		names[secretName(obj)] = true
+	}
+	for _, c := range creds {
+		if !names[c.secretName] {
+			return nil, ErrSecretNotFound{Name: c.secretName, File: s.SecretsFile}
+		}
+	}
+
+	info, err := os.Stat(s.SecretsFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(s.SecretsFile)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(s.SecretsFile, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err = f.Close(); err != nil {
+		return nil, err
+	}
+
+	file := &secretsFile{path: s.SecretsFile, mode: info.Mode().Perm(), data: data}
+	if secret.ContainsEncryptedValues(data) {
+		var key string
+		if key, err = secret.SiteKey(); err != nil {
+			return nil, err
+		}
+		if file.cipher, err = secret.NewCipher(key); err != nil {
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+// changePassword sets the password on every host of the credentials and
+// checks that the hosts accept it. Account services of all hosts are set up
+// before any password is changed, the previous password is restored on all
+// changed hosts if any of them fails
+func (s *BMCSettings) changePassword(ctx context.Context, c *credentials, password string) error {
+	newAccountService := s.accountServiceFactory()
+
+	services := make([]AccountService, 0, len(c.hosts))
+	for _, host := range c.hosts {
+		address, err := document.GetBMHBMCAddress(host)
+		if err != nil {
+			return err
+		}
+		service, err := newAccountService(address, c.username, c.password)
+		if err != nil {
+			return err
+		}
+		services = append(services, service)
+	}
+
+	var changed []document.Document
+	for i, host := range c.hosts {
+		err := services[i].ChangePassword(ctx, password)
+		if err == nil {
+			changed = append(changed, host)
+			err = services[i].VerifyLogin(ctx, password)
+		}
+		if err != nil {
+			failed := ErrRotationFailed{
+				Host:        host.GetName(),
+				Err:         err,
+				Unrecovered: restorePassword(ctx, newAccountService, changed, c, password),
+			}
+			if len(failed.Unrecovered) > 0 {
+				failed.Password = password
+				failed.RecoveryFile, failed.RecoveryErr = writeRecoveryFile(c.secretName, password)
+			}
+			return failed
+		}
+	}
+	return nil
+}
+
+// writeRecoveryFile writes the password kept by hosts whose previous password
+// can't be restored to a temporary file, which is readable by the user only,
+// and returns its path
+func writeRecoveryFile(secretName, password string) (string, error) {
+	f, err := ioutil.TempFile("", "airship-bmc-"+secretName+"-")
+	if err != nil {
+		return "", err
+	}
+	if _, err = f.WriteString(password + "\n"); err != nil {
+		f.Close()
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+// restorePassword sets the previous password of the credentials on the hosts
+// and returns names of hosts where it fails
+func restorePassword(ctx context.Context, newAccountService AccountServiceFactory,
+	hosts []document.Document, c *credentials, password string) []string {
+	var failed []string
+	for _, host := range hosts {
+		address, err := document.GetBMHBMCAddress(host)
+		if err == nil {
+			var service AccountService
+			if service, err = newAccountService(address, c.username, password); err == nil {
+				err = service.ChangePassword(ctx, c.password)
+			}
+		}
+		if err != nil {
+			failed = append(failed, host.GetName())
+		}
+	}
+	return failed
+}
+
+// accountServiceFactory returns NewAccountService or the Redfish
+// AccountService factory if it is not set
+func (s *BMCSettings) accountServiceFactory() AccountServiceFactory {
+	if s.NewAccountService != nil {
+		return s.NewAccountService
+	}
+	return s.redfishAccountService
+}
+
+func (s *BMCSettings) redfishAccountService(address, username, password string) (AccountService, error) {
+	client, err := redfish.NewAccountClient(address, s.Insecure, s.UseProxy, username, password)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// setPassword returns contents of the file with the password of the Secret
+// replaced, the password is encrypted with the site key if the previous one
+// was encrypted
+func (f *secretsFile) setPassword(name, password string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := secret.TransformSecrets(bytes.NewReader(f.data), buf, func(obj map[string]interface{}) error {
+		if secretName(obj) != name {
+			return nil
+		}
+		return setPassword(obj, password, f.cipher)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// write replaces contents of the file keeping its mode
+func (f *secretsFile) write(data []byte) error {
+	if err := ioutil.WriteFile(f.path, data, f.mode); err != nil {
+		return err
+	}
+	f.data = data
+	return nil
+}
+
+// setPassword replaces the password in data and stringData of the Secret
+// object, the password is added to data if neither has it. Passwords which
+// were encrypted are encrypted with the cipher
+func setPassword(obj map[string]interface{}, password string, c *secret.Cipher) error {
+	values := map[string]string{
+		"data":       base64.StdEncoding.EncodeToString([]byte(password)),
+		"stringData": password,
+	}
+	updated := false
+	for _, field := range []string{"data", "stringData"} {
+		data, ok := obj[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		previous, ok := data[PasswordKey].(string)
+		if !ok {
+			continue
+		}
+		value := values[field]
+		if secret.IsEncrypted(previous) {
+			var err error
+			if value, err = c.Encrypt(value, secret.AssociatedData(obj, PasswordKey)); err != nil {
+				return err
+			}
+		}
+		data[PasswordKey] = value
+		updated = true
+	}
+
+	if !updated {
+		data, ok := obj["data"].(map[string]interface{})
+		if !ok {
+			data = make(map[string]interface{})
+			obj["data"] = data
+		}
+		data[PasswordKey] = values["data"]
+	}
+	return nil
+}
+
+func secretName(obj map[string]interface{}) string {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	return name
+}
@@ -0,0 +1,318 @@
+package rotate_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/secret"
+	"opendev.org/airship/airshipctl/pkg/secret/rotate"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const (
+	node01Address = "redfish+https://10.23.25.1/redfish/v1/Systems/node01"
+	node02Address = "redfish+https://10.23.25.2/redfish/v1/Systems/node02"
+	node03Address = "redfish+https://10.23.25.3/redfish/v1/Systems/node03"
+
+	secretsFixture = "testdata/primary/site/test-site/target/secrets.yaml"
+)
+
+// fakeBMCs keeps passwords of BMCs by their addresses
+type fakeBMCs struct {
+	passwords map[string]string
+	// broken BMCs accept new passwords but never let users log in with them
+	broken map[string]bool
+	// frozen BMCs refuse password changes
+	frozen map[string]bool
+	// changed is called after a password is changed if it is set
+	changed func()
+}
+
+func newFakeBMCs() *fakeBMCs {
+	return &fakeBMCs{
+		passwords: map[string]string{
+			node01Address: "old-node01",
+			node02Address: "old-rack",
+			node03Address: "old-rack",
+		},
+		broken: map[string]bool{},
+		frozen: map[string]bool{},
+	}
+}
+
+func (f *fakeBMCs) accountService(address, _, password string) (rotate.AccountService, error) {
+	return &fakeAccount{bmcs: f, address: address, password: password}, nil
+}
+
+type fakeAccount struct {
+	bmcs     *fakeBMCs
+	address  string
+	password string
+}
+
+func (a *fakeAccount) ChangePassword(_ context.Context, newPassword string) error {
+	if a.bmcs.passwords[a.address] != a.password {
+		return errors.New("unauthorized")
+	}
+	if a.bmcs.frozen[a.address] {
+		return errors.New("password changes are disabled")
+	}
+	a.bmcs.passwords[a.address] = newPassword
+	if a.bmcs.changed != nil {
+		a.bmcs.changed()
+	}
+	return nil
+}
+
+func (a *fakeAccount) VerifyLogin(_ context.Context, password string) error {
+	if a.bmcs.broken[a.address] || a.bmcs.passwords[a.address] != password {
+		return errors.New("unauthorized")
+	}
+	return nil
+}
+
+func TestRotate(t *testing.T) {
+	bmcs := newFakeBMCs()
+	settings, cleanup := makeBMCSettings(t, bmcs)
+	defer cleanup(t)
+	settings.All = true
+
+	out := &bytes.Buffer{}
+	require.NoError(t, settings.Rotate(out))
+	assert.Equal(t, `BMC password of host "node01" rotated, Secret "node01-bmc" updated.
+BMC password of host "node02" rotated, Secret "rack-bmc" updated.
+BMC password of host "node03" rotated, Secret "rack-bmc" updated.
+`, out.String())
+
+	passwords := readPasswords(t, settings.SecretsFile)
+	assert.Equal(t, bmcs.passwords[node01Address], passwords["node01-bmc"])
+	assert.NotEqual(t, "old-node01", passwords["node01-bmc"])
+	assert.Len(t, passwords["node01-bmc"], 24)
+	// hosts sharing the secret get the same password
+	assert.Equal(t, bmcs.passwords[node02Address], passwords["rack-bmc"])
+	assert.Equal(t, bmcs.passwords[node03Address], passwords["rack-bmc"])
+	assert.NotEqual(t, "old-rack", passwords["rack-bmc"])
+}
+
+func TestRotateSelectedHosts(t *testing.T) {
+	bmcs := newFakeBMCs()
+	settings, cleanup := makeBMCSettings(t, bmcs)
+	defer cleanup(t)
+	settings.Hosts = []string{"node01"}
+	settings.Policy = &secret.PassphrasePolicy{Length: 3, Words: true}
+
+	require.NoError(t, settings.Rotate(ioutil.Discard))
+
+	passwords := readPasswords(t, settings.SecretsFile)
+	assert.Equal(t, bmcs.passwords[node01Address], passwords["node01-bmc"])
+	assert.Regexp(t, `^[a-z]+-[a-z]+-[a-z]+$`, passwords["node01-bmc"])
+	assert.Equal(t, "old-rack", passwords["rack-bmc"])
+	assert.Equal(t, "old-rack", bmcs.passwords[node02Address])
+}
+
+func TestRotateEncryptedSecrets(t *testing.T) {
+	defer testutil.SetEnv(t, secret.SiteKeyEnv, "test-site-key")()
+	bmcs := newFakeBMCs()
+	settings, cleanup := makeBMCSettings(t, bmcs)
+	defer cleanup(t)
+	settings.All = true
+	require.NoError(t, (&secret.EncryptionSettings{Src: settings.SecretsFile, Dst: settings.SecretsFile}).Encrypt(nil))
+
+	require.NoError(t, settings.Rotate(ioutil.Discard))
+
+	data, err := ioutil.ReadFile(settings.SecretsFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), bmcs.passwords[node02Address])
+	passwords := readPasswords(t, settings.SecretsFile)
+	assert.Equal(t, bmcs.passwords[node01Address], passwords["node01-bmc"])
+	assert.Equal(t, bmcs.passwords[node02Address], passwords["rack-bmc"])
+}
+
+func TestRotateVerificationFailure(t *testing.T) {
+	bmcs := newFakeBMCs()
+	bmcs.broken[node03Address] = true
+	settings, cleanup := makeBMCSettings(t, bmcs)
+	defer cleanup(t)
+	settings.Hosts = []string{"node02", "node03"}
+
+	err := settings.Rotate(ioutil.Discard)
+	assert.Equal(t, rotate.ErrRotationFailed{Host: "node03", Err: errors.New("unauthorized")}, err)
+
+	// passwords are restored and the secrets file is left as is
+	assert.Equal(t, newFakeBMCs().passwords, bmcs.passwords)
+	expected, err := ioutil.ReadFile(secretsFixture)
+	require.NoError(t, err)
+	actual, err := ioutil.ReadFile(settings.SecretsFile)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestRotateMissingAddress(t *testing.T) {
+	bmcs := newFakeBMCs()
+	settings, cleanup := makeBMCSettings(t, bmcs)
+	defer cleanup(t)
+	manifest, err := settings.Config().CurrentContextManifest()
+	require.NoError(t, err)
+	manifest.SubPath = "primary/site/no-bmc-address"
+	settings.All = true
+
+	// the second host has no BMC address, no password is changed
+	assert.Error(t, settings.Rotate(ioutil.Discard))
+	assert.Equal(t, newFakeBMCs().passwords, bmcs.passwords)
+}
+
+func TestRotateRestoreFailure(t *testing.T) {
+	bmcs := newFakeBMCs()
+	bmcs.broken[node03Address] = true
+	// node02 keeps the new password since it refuses to change it back
+	bmcs.changed = func() { bmcs.frozen[node02Address] = true }
+	settings, cleanup := makeBMCSettings(t, bmcs)
+	defer cleanup(t)
+	settings.Hosts = []string{"node02", "node03"}
+
+	err := settings.Rotate(ioutil.Discard)
+	require.IsType(t, rotate.ErrRotationFailed{}, err)
+	failed := err.(rotate.ErrRotationFailed)
+	defer os.Remove(failed.RecoveryFile)
+	assert.Equal(t, []string{"node02"}, failed.Unrecovered)
+	assert.Equal(t, bmcs.passwords[node02Address], failed.Password)
+	assert.Equal(t, "old-rack", bmcs.passwords[node03Address])
+
+	// the password is saved in the recovery file instead of the message
+	require.NoError(t, failed.RecoveryErr)
+	assert.NotContains(t, failed.Error(), failed.Password)
+	assert.Contains(t, failed.Error(), failed.RecoveryFile)
+	info, err := os.Stat(failed.RecoveryFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	saved, err := ioutil.ReadFile(failed.RecoveryFile)
+	require.NoError(t, err)
+	assert.Equal(t, failed.Password+"\n", string(saved))
+}
+
+func TestRotateSecretsFileFailure(t *testing.T) {
+	bmcs := newFakeBMCs()
+	settings, cleanup := makeBMCSettings(t, bmcs)
+	defer cleanup(t)
+	settings.Hosts = []string{"node02", "node03"}
+	// the secrets file can't be written once passwords are changed
+	bmcs.changed = func() {
+		require.NoError(t, os.RemoveAll(settings.SecretsFile))
+		require.NoError(t, os.Mkdir(settings.SecretsFile, 0700))
+	}
+
+	err := settings.Rotate(ioutil.Discard)
+	require.IsType(t, rotate.ErrSecretNotUpdated{}, err)
+	notUpdated := err.(rotate.ErrSecretNotUpdated)
+	assert.Equal(t, "rack-bmc", notUpdated.Name)
+	assert.Empty(t, notUpdated.Unrecovered)
+	assert.Empty(t, notUpdated.Password)
+	assert.Empty(t, notUpdated.RecoveryFile)
+
+	// passwords are restored
+	assert.Equal(t, newFakeBMCs().passwords, bmcs.passwords)
+}
+
+func TestRotateErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		hosts       []string
+		secrets     string
+		expectedErr error
+	}{
+		{
+			name:        "no-hosts",
+			expectedErr: rotate.ErrNoHostsSelected{},
+		},
+		{
+			name:        "unknown-host",
+			hosts:       []string{"node01", "node04"},
+			expectedErr: rotate.ErrHostNotFound{Name: "node04"},
+		},
+		{
+			name:    "missing-secret",
+			hosts:   []string{"node02"},
+			secrets: "---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: node01-bmc\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			bmcs := newFakeBMCs()
+			settings, cleanup := makeBMCSettings(t, bmcs)
+			defer cleanup(t)
+			settings.Hosts = tt.hosts
+			expectedErr := tt.expectedErr
+			if tt.secrets != "" {
+				require.NoError(t, ioutil.WriteFile(settings.SecretsFile, []byte(tt.secrets), 0600))
+				expectedErr = rotate.ErrSecretNotFound{Name: "rack-bmc", File: settings.SecretsFile}
+			}
+
+			assert.Equal(t, expectedErr, settings.Rotate(ioutil.Discard))
+			assert.Equal(t, newFakeBMCs().passwords, bmcs.passwords)
+		})
+	}
+}
+
+// makeBMCSettings returns settings updating a copy of the secrets of the
+// test manifest
+func makeBMCSettings(t *testing.T, bmcs *fakeBMCs) (*rotate.BMCSettings, func(*testing.T)) {
+	t.Helper()
+	rs := &environment.AirshipCTLSettings{}
+
+	akp, err := filepath.Abs("testdata/kubeconfig.yaml")
+	require.NoError(t, err)
+
+	adir, err := filepath.Abs("testdata/config.yaml")
+	require.NoError(t, err)
+
+	rs.SetAirshipConfigPath(adir)
+	rs.SetKubeConfigPath(akp)
+	rs.InitConfig()
+
+	tempDir, cleanup := testutil.TempDir(t, "airship-rotate-bmc")
+	secrets, err := ioutil.ReadFile(secretsFixture)
+	require.NoError(t, err)
+	secretsFile := filepath.Join(tempDir, "secrets.yaml")
+	require.NoError(t, ioutil.WriteFile(secretsFile, secrets, 0600))
+
+	return &rotate.BMCSettings{
+		AirshipCTLSettings: rs,
+		ClusterType:        "target",
+		SecretsFile:        secretsFile,
+		NewAccountService:  bmcs.accountService,
+	}, cleanup
+}
+
+// readPasswords returns BMC passwords of the secrets file by Secret names
+func readPasswords(t *testing.T, path string) map[string]string {
+	t.Helper()
+	secrets, err := secret.ReadSecrets(path)
+	require.NoError(t, err)
+
+	passwords := make(map[string]string, len(secrets))
+	for _, obj := range secrets {
+		metadata := obj["metadata"].(map[string]interface{})
+		name := metadata["name"].(string)
+		if stringData, ok := obj["stringData"].(map[string]interface{}); ok {
+			passwords[name] = stringData[rotate.PasswordKey].(string)
+			continue
+		}
+		data := obj["data"].(map[string]interface{})
+		decoded, err := base64.StdEncoding.DecodeString(data[rotate.PasswordKey].(string))
+		require.NoError(t, err)
+		passwords[name] = string(decoded)
+	}
+	return passwords
+}
@@ -0,0 +1,87 @@
+package rotate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrNoHostsSelected returned if neither hosts nor all hosts are selected
+type ErrNoHostsSelected struct{}
+
+func (e ErrNoHostsSelected) Error() string {
+	return "No hosts selected, specify hosts by name or select all hosts"
+}
+
+// ErrHostNotFound returned if the BareMetalHost is not found in the bundle
+type ErrHostNotFound struct {
+	Name string
+}
+
+func (e ErrHostNotFound) Error() string {
+	return fmt.Sprintf("BareMetalHost %q is not found", e.Name)
+}
+
+// ErrSecretNotFound returned if the BMC credentials Secret is not found in
+// the secrets file
+type ErrSecretNotFound struct {
+	Name string
+	File string
+}
+
+func (e ErrSecretNotFound) Error() string {
+	return fmt.Sprintf("Secret %q is not found in %s", e.Name, e.File)
+}
+
+// ErrRotationFailed returned if the password of the host can't be changed
+// or the host doesn't accept the new password, the secrets file is not
+// updated in this case
+type ErrRotationFailed struct {
+	Host string
+	Err  error
+	// Unrecovered are hosts whose password was changed but can't be
+	// rolled back, Password is the password they keep. It is written to
+	// RecoveryFile unless writing fails with RecoveryErr
+	Unrecovered  []string
+	Password     string
+	RecoveryFile string
+	RecoveryErr  error
+}
+
+func (e ErrRotationFailed) Error() string {
+	msg := fmt.Sprintf("Failed to rotate BMC password of host %q: %v", e.Host, e.Err)
+	return msg + unrecoveredMessage(e.Unrecovered, e.RecoveryFile, e.RecoveryErr)
+}
+
+// ErrSecretNotUpdated returned if the secrets file can't be written after the
+// password of the hosts is changed, the previous password is restored on the
+// hosts in this case
+type ErrSecretNotUpdated struct {
+	Name string
+	File string
+	Err  error
+	// Unrecovered are hosts whose password can't be rolled back, Password
+	// is the password they keep. It is written to RecoveryFile unless
+	// writing fails with RecoveryErr
+	Unrecovered  []string
+	Password     string
+	RecoveryFile string
+	RecoveryErr  error
+}
+
+func (e ErrSecretNotUpdated) Error() string {
+	msg := fmt.Sprintf("Failed to update Secret %q in %s: %v", e.Name, e.File, e.Err)
+	return msg + unrecoveredMessage(e.Unrecovered, e.RecoveryFile, e.RecoveryErr)
+}
+
+// unrecoveredMessage tells where the password of hosts whose previous
+// password can't be restored is saved, the password itself is never printed
+func unrecoveredMessage(hosts []string, recoveryFile string, recoveryErr error) string {
+	if len(hosts) == 0 {
+		return ""
+	}
+	msg := "; failed to restore the previous password of hosts " + strings.Join(hosts, ", ")
+	if recoveryErr != nil {
+		return msg + fmt.Sprintf(", their password can't be saved: %v", recoveryErr)
+	}
+	return msg + ", their password is saved in " + recoveryFile
+}
@@ -0,0 +1,306 @@
+package secret
+
+import (
+	"bytes"
+	crypto "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"math/rand"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Group, version and kind of documents declaring site secrets
+const (
+	Group           = "airshipit.org"
+	Version         = "v1alpha1"
+	SiteSecretsKind = "SiteSecrets"
+)
+
+// TypeAnnotation is the annotation of generated Secret documents holding the
+// type of the secret they were generated for
+const TypeAnnotation = "airshipit.org/secret-type"
+
+// Types of generated secrets
+const (
+	PassphraseType           = "passphrase"
+	SSHKeypairType           = "sshKeypair"
+	CertificateAuthorityType = "certificateAuthority"
+	CertificateType          = "certificate"
+	KubeadmTokenType         = "kubeadmToken"
+)
+
+// DefaultSSHKeyBits is the size of SSH keys generated if the size is not set
+const DefaultSSHKeyBits = 4096
+
+// Keys of data of generated Secret documents
+const (
+	PassphraseKey    = "password"
+	SSHPrivateKeyKey = "ssh-privatekey"
+	SSHPublicKeyKey  = "ssh-publickey"
+	TLSCertKey       = "tls.crt"
+	TLSKeyKey        = "tls.key"
+	CACertKey        = "ca.crt"
+	TokenKey         = "token"
+	TokenIDKey       = "token-id"
+	TokenSecretKey   = "token-secret"
+)
+
+const tokenChars = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// SiteSecrets is a document declaring secrets required by the site
+type SiteSecrets struct {
+	Secrets []SecretSpec `json:"secrets"`
+}
+
+// SecretSpec declares a secret to generate
+type SecretSpec struct {
+	// Name and Namespace of the generated Secret document
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// Type is the type of the secret, one of passphrase, sshKeypair,
+	// certificateAuthority, certificate and kubeadmToken
+	Type string `json:"type"`
+	// Length of passphrases
+	Length int `json:"length,omitempty"`
+	// Policy of passphrases, Length is ignored if it is set
+	Policy *PassphrasePolicy `json:"policy,omitempty"`
+	// Bits is the size of keys of SSH keypairs and certificates
+	Bits int `json:"bits,omitempty"`
+	// CommonName, Organization and Hosts of certificates
+	CommonName   string   `json:"commonName,omitempty"`
+	Organization []string `json:"organization,omitempty"`
+	Hosts        []string `json:"hosts,omitempty"`
+	// CA is the name of the certificate authority signing the certificate
+	CA string `json:"ca,omitempty"`
+	// Validity of certificates, such as 8760h
+	Validity string `json:"validity,omitempty"`
+}
+
+// Generator generates secrets keeping the ones generated before
+type Generator struct {
+	existing    map[string]map[string]interface{}
+	authorities map[string]*KeyPair
+	passphrases *PassphraseEngine
+	rng         *rand.Rand
+}
+
+// NewGenerator returns Generator keeping existing Secret documents
+func NewGenerator(existing []map[string]interface{}) *Generator {
+	g := &Generator{
+		existing:    make(map[string]map[string]interface{}, len(existing)),
+		authorities: make(map[string]*KeyPair),
+		passphrases: NewPassphraseEngine(nil),
+		rng:         rand.New(&Source{}),
+	}
+	for _, obj := range existing {
+		g.existing[secretID(objectMeta(obj, "namespace"), objectMeta(obj, "name"))] = obj
+	}
+	return g
+}
+
+// Generate returns Secret documents for the specs in the same order. A
+// secret is kept if a Secret document of the same name and type exists,
+// certificates are also regenerated if their authority has changed
+func (g *Generator) Generate(specs []SecretSpec) ([]map[string]interface{}, error) {
+	secrets := make([]map[string]interface{}, len(specs))
+	// certificate authorities go first so that certificates can be
+	// signed regardless of the order of the specs
+	for i, spec := range specs {
+		if spec.Type != CertificateAuthorityType {
+			continue
+		}
+		obj, err := g.generate(spec)
+		if err != nil {
+			return nil, err
+		}
+		secrets[i] = obj
+	}
+	for i, spec := range specs {
+		if spec.Type == CertificateAuthorityType {
+			continue
+		}
+		obj, err := g.generate(spec)
+		if err != nil {
+			return nil, err
+		}
+		secrets[i] = obj
+	}
+	return secrets, nil
+}
+
+func (g *Generator) generate(spec SecretSpec) (map[string]interface{}, error) {
+	existing := g.existing[secretID(spec.Namespace, spec.Name)]
+	if existing != nil && !g.isCurrent(spec, existing) {
+		existing = nil
+	}
+
+	switch spec.Type {
+	case PassphraseType:
+		if existing != nil {
+			return existing, nil
+		}
+		passphrase := g.passphrases.GeneratePassphraseN(spec.Length)
+		if spec.Policy != nil {
+			var err error
+			if passphrase, err = g.passphrases.GeneratePolicyPassphrase(spec.Policy); err != nil {
+				return nil, ErrInvalidSecretSpec{Name: spec.Name, Msg: err.Error()}
+			}
+		}
+		return newSecret(spec, "Opaque", map[string][]byte{PassphraseKey: []byte(passphrase)}), nil
+	case SSHKeypairType:
+		if existing != nil {
+			return existing, nil
+		}
+		return g.sshKeypair(spec)
+	case CertificateAuthorityType:
+		return g.certificateAuthority(spec, existing)
+	case CertificateType:
+		if existing != nil {
+			return existing, nil
+		}
+		return g.certificate(spec)
+	case KubeadmTokenType:
+		if existing != nil {
+			return existing, nil
+		}
+		id, secret := g.randomString(6), g.randomString(16)
+		return newSecret(spec, "Opaque", map[string][]byte{
+			TokenKey:       []byte(id + "." + secret),
+			TokenIDKey:     []byte(id),
+			TokenSecretKey: []byte(secret),
+		}), nil
+	default:
+		return nil, ErrInvalidSecretSpec{Name: spec.Name, Msg: "unknown type " + spec.Type}
+	}
+}
+
+// isCurrent tells if the existing Secret document is generated for the spec
+// and, for certificates, signed by the current certificate authority
+func (g *Generator) isCurrent(spec SecretSpec, obj map[string]interface{}) bool {
+	annotations, _ := objectField(obj, "metadata")["annotations"].(map[string]interface{})
+	if annotations[TypeAnnotation] != spec.Type {
+		return false
+	}
+	if spec.Type != CertificateType {
+		return true
+	}
+	ca, found := g.authorities[spec.CA]
+	return found && bytes.Equal(SecretData(obj, CACertKey), ca.CertificatePEM())
+}
+
+func (g *Generator) sshKeypair(spec SecretSpec) (map[string]interface{}, error) {
+	bits := spec.Bits
+	if bits == 0 {
+		bits = DefaultSSHKeyBits
+	}
+	key, err := rsa.GenerateKey(crypto.Reader, bits)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := ssh.NewPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return newSecret(spec, "kubernetes.io/ssh-auth", map[string][]byte{
+		SSHPrivateKeyKey: EncodePrivateKey(key),
+		SSHPublicKeyKey:  ssh.MarshalAuthorizedKey(publicKey),
+	}), nil
+}
+
+func (g *Generator) certificateAuthority(spec SecretSpec, existing map[string]interface{}) (map[string]interface{}, error) {
+	if existing != nil {
+		kp, err := ParseKeyPair(SecretData(existing, TLSCertKey), SecretData(existing, TLSKeyKey))
+		if err == nil {
+			g.authorities[spec.Name] = kp
+			return existing, nil
+		}
+	}
+
+	config, err := certificateConfig(spec)
+	if err != nil {
+		return nil, err
+	}
+	kp, err := NewCertificateAuthority(config)
+	if err != nil {
+		return nil, err
+	}
+	g.authorities[spec.Name] = kp
+	return newSecret(spec, "kubernetes.io/tls", map[string][]byte{
+		TLSCertKey: kp.CertificatePEM(),
+		TLSKeyKey:  kp.KeyPEM(),
+	}), nil
+}
+
+func (g *Generator) certificate(spec SecretSpec) (map[string]interface{}, error) {
+	ca, found := g.authorities[spec.CA]
+	if !found {
+		return nil, ErrInvalidSecretSpec{Name: spec.Name, Msg: "unknown certificate authority " + spec.CA}
+	}
+	config, err := certificateConfig(spec)
+	if err != nil {
+		return nil, err
+	}
+	kp, err := ca.NewCertificate(config)
+	if err != nil {
+		return nil, err
+	}
+	return newSecret(spec, "kubernetes.io/tls", map[string][]byte{
+		TLSCertKey: kp.CertificatePEM(),
+		TLSKeyKey:  kp.KeyPEM(),
+		CACertKey:  ca.CertificatePEM(),
+	}), nil
+}
+
+func (g *Generator) randomString(length int) string {
+	var sb strings.Builder
+	for i := 0; i < length; i++ {
+		sb.WriteByte(tokenChars[g.rng.Intn(len(tokenChars))])
+	}
+	return sb.String()
+}
+
+func certificateConfig(spec SecretSpec) (CertificateConfig, error) {
+	config := CertificateConfig{
+		CommonName:   spec.CommonName,
+		Organization: spec.Organization,
+		Hosts:        spec.Hosts,
+		Bits:         spec.Bits,
+	}
+	if config.CommonName == "" {
+		config.CommonName = spec.Name
+	}
+	if spec.Type == CertificateType {
+		config.Usages = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	}
+	if spec.Validity != "" {
+		validity, err := time.ParseDuration(spec.Validity)
+		if err != nil {
+			return config, ErrInvalidSecretSpec{Name: spec.Name, Msg: err.Error()}
+		}
+		config.Validity = validity
+	}
+	return config, nil
+}
+
+func newSecret(spec SecretSpec, secretType string, data map[string][]byte) map[string]interface{} {
+	obj := NewSecret(spec.Name, spec.Namespace, secretType, data)
+	objectField(obj, "metadata")["annotations"] = map[string]interface{}{TypeAnnotation: spec.Type}
+	return obj
+}
+
+func objectField(obj map[string]interface{}, field string) map[string]interface{} {
+	value, _ := obj[field].(map[string]interface{})
+	return value
+}
+
+func objectMeta(obj map[string]interface{}, field string) string {
+	value, _ := objectField(obj, "metadata")[field].(string)
+	return value
+}
+
+func secretID(namespace, name string) string {
+	return namespace + "/" + name
+}
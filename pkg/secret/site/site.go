@@ -0,0 +1,95 @@
+package site
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/secret"
+)
+
+// SecretsPath is a path of the bundle with site secrets documents relative
+// to the sub path of the current context manifest
+const SecretsPath = "secrets"
+
+// Settings for secret generate site command
+type Settings struct {
+	*environment.AirshipCTLSettings
+	// Dst is the file to write generated documents to, documents are
+	// written to the writer passed to Generate if it is empty. Secrets
+	// found in Dst are kept as is
+	Dst string
+	// Encrypt tells to encrypt generated documents with the site key
+	Encrypt bool
+}
+
+// SiteSecrets returns secrets declared in the secrets bundle of the current
+// context manifest
+func (s *Settings) SiteSecrets() ([]secret.SecretSpec, error) {
+	conf := s.Config()
+	if err := conf.EnsureComplete(); err != nil {
+		return nil, err
+	}
+
+	secretsPath, err := conf.CurrentContextBundlePath(SecretsPath)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := document.NewBundleByPath(secretsPath)
+	if err != nil {
+		return nil, err
+	}
+	return LoadSecrets(bundle)
+}
+
+// Generate generates secrets declared in the site manifests, secrets
+// generated by previous runs are read from Dst and kept as is
+func (s *Settings) Generate(out io.Writer) error {
+	specs, err := s.SiteSecrets()
+	if err != nil {
+		return err
+	}
+	existing, err := secret.ReadSecrets(s.Dst)
+	if err != nil {
+		return err
+	}
+	secrets, err := secret.NewGenerator(existing).Generate(specs)
+	if err != nil {
+		return err
+	}
+	return secret.WriteSecrets(out, s.Dst, secrets, s.Encrypt)
+}
+
+// LoadSecrets returns secrets declared in the bundle, secrets of several
+// documents are ordered by names of the documents
+func LoadSecrets(bundle document.Bundle) ([]secret.SecretSpec, error) {
+	docs, err := bundle.GetByGvk(secret.Group, secret.Version, secret.SiteSecretsKind)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].GetName() < docs[j].GetName() })
+
+	var specs []secret.SecretSpec
+	names := make(map[string]bool)
+	for _, doc := range docs {
+		data, err := doc.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		siteSecrets := &secret.SiteSecrets{}
+		if err = json.Unmarshal(data, siteSecrets); err != nil {
+			return nil, err
+		}
+		for _, spec := range siteSecrets.Secrets {
+			id := spec.Namespace + "/" + spec.Name
+			if names[id] {
+				return nil, secret.ErrDuplicateSecret{Name: spec.Name}
+			}
+			names[id] = true
+			specs = append(specs, spec)
+		}
+	}
+	return specs, nil
+}
@@ -0,0 +1,159 @@
+package site_test
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/secret"
+	"opendev.org/airship/airshipctl/pkg/secret/site"
+	"opendev.org/airship/airshipctl/testutil"
+)
+
+const testSiteKey = "test-site-key"
+
+func TestLoadSecrets(t *testing.T) {
+	specs, err := site.LoadSecrets(testutil.NewTestBundle(t, "testdata/primary/site/test-site/secrets"))
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		names = append(names, spec.Name)
+	}
+	assert.Equal(t, []string{
+		"bmc-password",
+		"deploy-ssh-key",
+		"api-serving-cert",
+		"site-ca",
+		"bootstrap-token",
+	}, names)
+
+	_, err = site.LoadSecrets(testutil.NewTestBundle(t, "testdata/duplicate"))
+	assert.Equal(t, secret.ErrDuplicateSecret{Name: "bmc-password"}, err)
+}
+
+func TestGenerator(t *testing.T) {
+	specs, err := site.LoadSecrets(testutil.NewTestBundle(t, "testdata/primary/site/test-site/secrets"))
+	require.NoError(t, err)
+
+	secrets, err := secret.NewGenerator(nil).Generate(specs)
+	require.NoError(t, err)
+	require.Len(t, secrets, len(specs))
+
+	passphrase := secretData(t, secrets[0], secret.PassphraseKey)
+	assert.Len(t, passphrase, 32)
+	assert.Contains(t, string(secretData(t, secrets[1], secret.SSHPublicKeyKey)), "ssh-rsa ")
+	assert.Regexp(t, `^[a-z0-9]{6}\.[a-z0-9]{16}$`, string(secretData(t, secrets[4], secret.TokenKey)))
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(secretData(t, secrets[3], secret.TLSCertKey)))
+	cert := parseCertificate(t, secretData(t, secrets[2], secret.TLSCertKey))
+	_, err = cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "api.test-site"})
+	assert.NoError(t, err)
+	assert.Equal(t, "10.23.25.101", cert.IPAddresses[0].String())
+
+	// secrets of previous runs are kept
+	regenerated, err := secret.NewGenerator(secrets).Generate(specs)
+	require.NoError(t, err)
+	assert.Equal(t, secrets, regenerated)
+
+	// certificates are regenerated along with their authority
+	regenerated, err = secret.NewGenerator(secrets[:3]).Generate(specs)
+	require.NoError(t, err)
+	assert.Equal(t, passphrase, secretData(t, regenerated[0], secret.PassphraseKey))
+	assert.NotEqual(t, secrets[2], regenerated[2])
+	assert.Equal(t,
+		secretData(t, regenerated[3], secret.TLSCertKey),
+		secretData(t, regenerated[2], secret.CACertKey))
+}
+
+func TestGeneratorErrors(t *testing.T) {
+	tests := []struct {
+		spec        secret.SecretSpec
+		expectedErr error
+	}{
+		{
+			spec:        secret.SecretSpec{Name: "api", Type: "password"},
+			expectedErr: secret.ErrInvalidSecretSpec{Name: "api", Msg: "unknown type password"},
+		},
+		{
+			spec:        secret.SecretSpec{Name: "api", Type: secret.CertificateType, CA: "site-ca"},
+			expectedErr: secret.ErrInvalidSecretSpec{Name: "api", Msg: "unknown certificate authority site-ca"},
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := secret.NewGenerator(nil).Generate([]secret.SecretSpec{tt.spec})
+		assert.Equal(t, tt.expectedErr, err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tempDir, cleanup := testutil.TempDir(t, "airship-site-secrets")
+	defer cleanup(t)
+	defer testutil.SetEnv(t, secret.SiteKeyEnv, testSiteKey)()
+
+	settings := makeSettings(t)
+	settings.Dst = filepath.Join(tempDir, "secrets.yaml")
+	settings.Encrypt = true
+	require.NoError(t, settings.Generate(nil))
+
+	generated, err := ioutil.ReadFile(settings.Dst)
+	require.NoError(t, err)
+	assert.Contains(t, string(generated), "ENC[AES256_GCM,")
+
+	decrypted := &bytes.Buffer{}
+	require.NoError(t, (&secret.EncryptionSettings{Src: settings.Dst}).Decrypt(decrypted))
+
+	// values generated by the previous run are kept
+	settings.Encrypt = false
+	require.NoError(t, settings.Generate(nil))
+	regenerated, err := ioutil.ReadFile(settings.Dst)
+	require.NoError(t, err)
+	testutil.AssertEqualDocuments(t, decrypted.Bytes(), regenerated)
+}
+
+func makeSettings(t *testing.T) *site.Settings {
+	t.Helper()
+	rs := &environment.AirshipCTLSettings{}
+
+	akp, err := filepath.Abs("testdata/kubeconfig.yaml")
+	require.NoError(t, err)
+
+	adir, err := filepath.Abs("testdata/config.yaml")
+	require.NoError(t, err)
+
+	rs.SetAirshipConfigPath(adir)
+	rs.SetKubeConfigPath(akp)
+	rs.InitConfig()
+
+	return &site.Settings{AirshipCTLSettings: rs}
+}
+
+func secretData(t *testing.T, obj map[string]interface{}, key string) []byte {
+	t.Helper()
+	data, ok := obj["data"].(map[string]interface{})
+	require.True(t, ok)
+	value, ok := data[key].(string)
+	require.True(t, ok)
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	require.NoError(t, err)
+	return decoded
+}
+
+func parseCertificate(t *testing.T, data []byte) *x509.Certificate {
+	t.Helper()
+	block, _ := pem.Decode(data)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
@@ -0,0 +1,89 @@
+package secret
+
+import "strings"
+
+// words are separated by white space to keep the list compact
+const words = `
+able acid acorn actor adapt add admit adopt adult aft again agent agree ahead aid aim air aisle
+alarm album alert algae alias alien align alike alive alley allow alloy alone alpha alpine also
+alter amber amble amend amino ample amuse angel angle ankle annex anvil apart apex apple apply apron
+aqua arbor arch arena argue arise arm armor army aroma array arrow art ash aside ask aspen asset
+atlas atom attic audio audit aunt auto avid avoid awake award aware axis bacon badge bagel baker
+balmy bamboo banjo bank barn baron basin basket bath baton beach beacon beam bean bear beard beast
+bed beef beetle begin being belt bench berry bike bingo birch bird bison bit blade blank blast blaze
+blend bless blimp blind blink bliss block blond bloom blue blunt blur board boast boat body bolt
+bond bone bonus book boost boot booth border boss botany bottle bounce bow bowl box brain brake
+brass brave bread breeze brick bride brief bring brisk broad brook broom brush bubble bucket buddy
+budget buffet bugle build bulb bulk bunch bunny burst bus bush butter button buyer buzz cabin cable
+cactus cage cake calm camel camp canal candle candy canoe canvas canyon cape car card cargo carol
+carpet carrot cart case cash cast castle cat catch cause cave cedar cell cement cereal chain chair
+chalk champ chant chaos charm chart chase cheek cheer cheese chef cherry chess chest chick chief
+child chili chimp chin chip chirp choice chord chorus chrome chunk cider cinema circle citrus city
+civic claim clam clap class claw clay clean clerk click cliff climb clinic clip cloak clock cloth
+cloud clover club clue coach coast coat cobra cocoa code coffee coil coin cola cold color colt comb
+comet comic coral cord core cork corn couch cough count court cousin cove cover cow crab craft crane
+crate crawl crayon cream credit creek crew cricket crisp crop cross crowd crown crumb crush crust
+cub cube cup curb curl curve cycle daily dairy daisy dance dash data date dawn deal debut decay
+decor deer delta demo denim dense depth desk dial diary dice diet digit dime diner dingo dish disk
+ditch diver dock doctor dodge dog doll dolphin dome donor donut door dose dot dove down dozen draft
+dragon drama drawer dream dress drift drill drink drive drum duck dune dust duty dwarf eager eagle
+early earth easel east easy echo edge edit eel egg elbow elder elect elf elite elk elm ember emit
+empty enamel enjoy enter entry envoy epic equal era erase error essay ethic even event exact exam
+exit expo extra eye fable fabric face fact fade fair fairy faith fall fame fancy farm fast fauna
+favor feast feather fence fern ferry fetch fever fiber field fig film final finch fire firm fish
+fist flag flame flash flask fleet flesh flint float flock flood floor flour flow fluid flute foam
+focus fog foil folk font food foot force forest fork fort forum fossil fox frame free fresh friend
+frog frost fruit fuel fun fund fuse gadget galaxy gale game gap garage garden garlic gas gate gauge
+gear gecko gem genre giant gift ginger giraffe given glad glass glide globe glove glow glue goat
+gold golf gong good goose gorge gown grace grain grant grape graph grass gravy green grid grill grin
+grip grove growl guard guava guess guest guide guitar gulf gull gum guru gust gym habit hair half
+hall halo hammer hand happy harbor hare harp hat hatch haven hawk hazel head heap heart heat hedge
+helmet help hemp herb hero heron hill hint hippo hobby hockey hold hole holly home honey hood hoof
+hook hope horn horse host hotel hour house human humor hunt hurry husky hut icon idea idle igloo
+image imply inch index ink inlet input insect into iris iron island item ivory ivy jacket jade
+jaguar jam jar jazz jeans jelly jewel job jog join joke jolly journal joy judge juice jumbo jump
+jungle junior jury just kayak keen kettle key kick kid kiln kind king kiosk kite kitten kiwi knee
+knife knock knot koala label lace ladder lake lamb lamp lance land lane lap laptop large laser latch
+later lava lawn layer leaf lean learn ledge lemon lens level lever lid life lift light lilac lily
+limb lime limit line linen lion lip liquid list liter live lizard llama load loaf lobby lobster
+local lock lodge logic loop lotus loud lounge love loyal lucky lumber lunar lunch lung lyric macaw
+magic magnet maid mail main major maker mall mango manor map maple marble march mark market mask
+mason mast match math maze meadow meal medal media melon memo menu merit mesa metal meter mild milk
+mill mime mind mint minute mirror mist mitten mix moat model modem mole moment monk month moon moose
+moral moss motel moth motor mouse mouth movie mud mule mural muse music mute myth nail name nap
+narrow navy near neck nectar needle neon nerve nest net never new news next nice niece night noble
+node noise noodle north nose note novel number nurse nut nylon oak oasis oat ocean octave odd offer
+office olive omega omen onion open opera optic oral orange orbit orchid order organ otter ounce
+outer oval oven owl owner ox oxygen oyster ozone pace pack paddle page pail paint palm panda panel
+pansy paper parade park parrot party pass pasta paste patch path patio pause peach peak pear pearl
+pecan pedal pen pencil penny pepper perch piano pick pie pier pig pilot pine pink pipe pitch pixel
+pizza place plain plan planet plank plant plate play plaza plot plum plus pocket poem poet point
+polar pole polka pond pony pool poppy porch port pose pot pouch pound powder power press price pride
+prime print prism prize probe prose proud prune pulse puma pump punch pupil puppy purse puzzle quail
+quart queen query quest quick quiet quilt quota quote rabbit race radar radio raft rail rain rally
+ranch range rapid raven ray razor reach ready realm recipe reef relax relay relic remedy rent reply
+rhino rhyme rice rider ridge ring ripple river road robin robot rock rocket rodeo roof room root
+rope rose rotor round route rover royal ruby rug ruler rumor rural rust saddle safe saga sage sail
+salad salmon salon salt sand satin sauce sauna scale scarf scene scent school scoop scout scrap
+screen scroll sea seal season seat seed sense serum shade shadow shark shawl sheep shelf shell
+shield shine ship shirt shoe shore shrimp shrub side sigh sign silk silver siren sister size skate
+sketch ski skill skirt skunk sky slab slate sled sleep slice slide slope sloth smile smoke snack
+snail snake sneeze snow soap soccer sock soda sofa soil solar solid solo sonar song soup south space
+spark spear spice spider spike spine spoon sport spot spray spring sprout spur squad squid stable
+stack staff stage stair stamp star start steam steel stem step stick stone stool storm story stove
+straw stream street stripe studio sugar suit summer sun surf swan sweater swing syrup table tablet
+taco tail talent tango tank tape target task taxi tea teacher team tent term test text theme thorn
+thumb ticket tide tiger tile timber tin tip titan toast today token tomato tone tool tooth topic
+torch total tour towel tower town toy track trade trail train tray treat tree trend trial tribe
+trick trophy trout truck trumpet trunk trust tuba tulip tuna tunnel turkey turtle tutor twig twin
+umbrella uncle union unit upper urban usual vacuum valley valve van vapor vase vault velvet vendor
+venue verb verse vessel vest veto video view villa vine vinyl violin visa visit visor vital vivid
+vocal voice volume vote voyage wafer wagon waist walk wall walnut walrus wand wave wax weasel web
+wedge week weight well whale wheat wheel whip whisk width wild willow wind window wine wing winter
+wire wise wish wizard wolf wombat wood wool word work world worm wrist yacht yard yarn year yeast
+yellow yoga yogurt young youth zebra zero zigzag zinc zipper zone zoo
+`
+
+// wordlist is the list of short common English words used for word
+// passphrases, every word adds about 10 bits of entropy
+var wordlist = strings.Fields(words)
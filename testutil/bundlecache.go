@@ -0,0 +1,15 @@
+package testutil
+
+import (
+	"os"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+// Tests never read or write the bundle cache of the user running them, tests
+// of the cache use caches in temporary directories
+func init() {
+	if err := os.Setenv(document.NoBundleCacheEnv, "true"); err != nil {
+		panic(err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
 )
 
 // UpdateGolden writes out the golden files with the latest values, rather than failing the test.
@@ -96,3 +97,33 @@ func checkError(t *testing.T, actual, expected error) {
 func normalize(in []byte) []byte {
 	return bytes.Replace(in, []byte("\r\n"), []byte("\n"), -1)
 }
+
+// AssertEqualDocuments asserts that YAML documents separated by "---" are
+// the same objects regardless of the formatting and the order of fields
+func AssertEqualDocuments(t *testing.T, expected, actual []byte) {
+	t.Helper()
+	expectedDocs := strings.Split(string(expected), "---\n")
+	actualDocs := strings.Split(string(actual), "---\n")
+	require.Len(t, actualDocs, len(expectedDocs))
+	for i := range expectedDocs {
+		var expectedObj, actualObj map[string]interface{}
+		require.NoError(t, yaml.Unmarshal([]byte(expectedDocs[i]), &expectedObj))
+		require.NoError(t, yaml.Unmarshal([]byte(actualDocs[i]), &actualObj))
+		assert.Equal(t, expectedObj, actualObj)
+	}
+}
+
+// SetEnv sets the environment variable and returns the function restoring
+// its previous value
+func SetEnv(t *testing.T, key, value string) (restore func()) {
+	t.Helper()
+	previous, found := os.LookupEnv(key)
+	require.NoError(t, os.Setenv(key, value))
+	return func() {
+		if found {
+			require.NoError(t, os.Setenv(key, previous))
+		} else {
+			require.NoError(t, os.Unsetenv(key))
+		}
+	}
+}